@@ -0,0 +1,77 @@
+// Command opsgenie-generate writes the Terraform configuration of the
+// resources in an existing OpsGenie account, together with import blocks, so
+// that the account can be brought under management of the provider.
+//
+// The API key is read from OPSGENIE_API_KEY, and the API of the account from
+// OPSGENIE_API_URL or OPSGENIE_REGION.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/opsgenie/terraform-provider-opsgenie/opsgenie"
+)
+
+func main() {
+	out := flag.String("out", "", "file to write the configuration to, instead of stdout")
+	types := flag.String("types", "", "comma separated resource types to generate, e.g. opsgenie_team,opsgenie_user (default all)")
+	flag.Parse()
+
+	// the provider logs through the standard logger, which Terraform usually
+	// collects; only show warnings and errors here
+	log.SetOutput(&levelFilter{w: os.Stderr})
+
+	opts := opsgenie.GenerateOptions{
+		ApiKey: os.Getenv("OPSGENIE_API_KEY"),
+		ApiUrl: os.Getenv("OPSGENIE_API_URL"),
+		Region: os.Getenv("OPSGENIE_REGION"),
+	}
+	if opts.ApiKey == "" {
+		fmt.Fprintln(os.Stderr, "OPSGENIE_API_KEY must be set")
+		os.Exit(2)
+	}
+	if *types != "" {
+		opts.ResourceTypes = strings.Split(*types, ",")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	buffered := bufio.NewWriter(w)
+	if err := opsgenie.GenerateConfig(context.Background(), opts, buffered); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := buffered.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// levelFilter drops log lines below [WARN].
+type levelFilter struct {
+	w io.Writer
+}
+
+func (f *levelFilter) Write(p []byte) (int, error) {
+	line := string(p)
+	if strings.Contains(line, "[WARN]") || strings.Contains(line, "[ERROR]") {
+		return f.w.Write(p)
+	}
+	return len(p), nil
+}
@@ -1,10 +1,12 @@
 module github.com/opsgenie/terraform-provider-opsgenie
 
-go 1.14
+go 1.15
 
 require (
 	github.com/hashicorp/go-retryablehttp v0.6.6
+	github.com/hashicorp/terraform-plugin-go v0.1.0
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.4.0
 	github.com/opsgenie/opsgenie-go-sdk-v2 v1.2.8
 	github.com/pkg/errors v0.8.1
+	github.com/sirupsen/logrus v1.4.2
 )
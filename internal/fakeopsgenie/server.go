@@ -0,0 +1,436 @@
+// Package fakeopsgenie provides an in-memory fake of the OpsGenie REST API,
+// so that the acceptance tests of the provider can run without an OpsGenie
+// account.
+//
+// The fake stores the JSON documents sent to the API as they are, and serves
+// them back on reads. It implements the generic behaviour of the endpoints
+// used by the provider, plus the side effects the provider relies on, like
+// the default schedule, escalation and routing rule of new teams. It does not
+// validate payloads the way the real API does.
+package fakeopsgenie
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// actions are the verbs that are posted to an item, e.g.
+// /v2/policies/{id}/enable, rather than naming a nested collection.
+var actions = map[string]bool{
+	"enable":          true,
+	"disable":         true,
+	"cancel":          true,
+	"change-order":    true,
+	"change-end-date": true,
+	"ping":            true,
+}
+
+// wrappedLists are the collections whose list response wraps the items in
+// an object, rather than returning them as data.
+var wrappedLists = map[string]string{
+	"/heartbeats":         "heartbeats",
+	"/incident-templates": "incidentTemplates",
+}
+
+// Server is a fake OpsGenie API served by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// ApiKey is the key that requests must authenticate with.
+	ApiKey string
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	logs        map[string][]map[string]interface{}
+	actions     map[string]interface{}
+}
+
+type collection struct {
+	ids   []string
+	items map[string]map[string]interface{}
+}
+
+// NewServer starts a fake OpsGenie API that accepts requests authenticated
+// with apiKey. Close must be called to stop it.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		ApiKey:      apiKey,
+		collections: make(map[string]*collection),
+		logs:        make(map[string][]map[string]interface{}),
+		actions:     make(map[string]interface{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// ApiUrl returns the host of the server, to be used as api_url of the
+// provider. The SDK uses plain HTTP for hosts that do not contain "api".
+func (s *Server) ApiUrl() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "GenieKey "+s.ApiKey {
+		writeError(w, http.StatusUnauthorized, "Could not authenticate")
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			body = make(map[string]interface{})
+		}
+	}
+
+	// drop the version, e.g. v2 in /v2/teams
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 {
+		writeError(w, http.StatusNotFound, "No handler found for "+r.URL.Path)
+		return
+	}
+	segments = segments[1:]
+
+	if segments[0] == "account" {
+		writeData(w, http.StatusOK, map[string]interface{}{"name": "fakeopsgenie", "userCount": 1})
+		return
+	}
+
+	if last := segments[len(segments)-1]; r.Method == http.MethodGet && strings.HasSuffix(last, ".ics") {
+		s.handleCalendar(w, segments)
+		return
+	}
+
+	key := ""
+	for i := 0; i < len(segments); i += 2 {
+		key = key + "/" + segments[i]
+		c := s.collection(key)
+
+		if i+1 == len(segments) {
+			s.handleCollection(w, r, key, c, body)
+			return
+		}
+
+		item := c.find(segments[i+1])
+		if item == nil {
+			if key == "/policies" && r.Method == http.MethodGet {
+				// alert and notification policies are listed by type
+				writeData(w, http.StatusOK, c.list(map[string]string{"type": segments[i+1]}))
+				return
+			}
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s with identifier [%s] not found", strings.TrimPrefix(key, "/"), segments[i+1]))
+			return
+		}
+		id := item["id"].(string)
+
+		if i+2 == len(segments) {
+			s.handleItem(w, r, key, c, id, body)
+			return
+		}
+
+		next := segments[i+2]
+		if i+3 == len(segments) {
+			switch {
+			case actions[next]:
+				s.handleAction(w, next, item, body)
+				return
+			case next == "actions":
+				s.handleSingleton(w, r, key+"/"+id+"/actions", body)
+				return
+			case next == "logs":
+				writeData(w, http.StatusOK, map[string]interface{}{"offset": "", "logs": s.logs[id]})
+				return
+			}
+		}
+
+		key = key + "/" + id
+	}
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request, key string, c *collection, body map[string]interface{}) {
+	switch r.Method {
+	case http.MethodGet:
+		items := c.list(nil)
+		if field, ok := wrappedLists[key]; ok {
+			writeData(w, http.StatusOK, map[string]interface{}{field: items})
+			return
+		}
+		writeData(w, http.StatusOK, items)
+	case http.MethodPost:
+		// names are only unique among top level resources
+		if name, ok := body["name"].(string); ok && !strings.Contains(key[1:], "/") && c.find(name) != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s with name [%s] already exists", strings.TrimPrefix(key, "/"), name))
+			return
+		}
+		item := s.create(key, c, body)
+		writeData(w, http.StatusCreated, item)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request, key string, c *collection, id string, body map[string]interface{}) {
+	item := c.items[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, item)
+	case http.MethodPatch, http.MethodPost:
+		for k, v := range body {
+			item[k] = v
+		}
+		s.log(key, id, "updated")
+		writeResult(w, "Updated", item)
+	case http.MethodPut:
+		replaced := body
+		for _, k := range []string{"id", "type", "apiKey", "ownerTeam", "createdAt"} {
+			if _, ok := replaced[k]; !ok && item[k] != nil {
+				replaced[k] = item[k]
+			}
+		}
+		c.items[id] = replaced
+		s.log(key, id, "updated")
+		writeResult(w, "Updated", replaced)
+	case http.MethodDelete:
+		c.delete(id)
+		writeResult(w, "Deleted", nil)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) handleAction(w http.ResponseWriter, action string, item map[string]interface{}, body map[string]interface{}) {
+	switch action {
+	case "enable":
+		item["enabled"] = true
+	case "disable":
+		item["enabled"] = false
+	case "cancel":
+		item["status"] = "cancelled"
+	case "change-order":
+		item["order"] = body["order"]
+	case "change-end-date":
+		if window, ok := item["time"].(map[string]interface{}); ok {
+			window["endDate"] = body["endDate"]
+		}
+	case "ping":
+		item["lastPingTime"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	writeResult(w, "Request will be processed", nil)
+}
+
+// handleCalendar serves the iCalendar exports of a schedule, e.g.
+// /v2/schedules/{id}.ics, and of the on-call periods of a user, e.g.
+// /v2/schedules/on-calls/{username}.ics, as calendars without events.
+func (s *Server) handleCalendar(w http.ResponseWriter, segments []string) {
+	identifier := strings.TrimSuffix(segments[len(segments)-1], ".ics")
+
+	var item map[string]interface{}
+	switch {
+	case len(segments) == 2 && segments[0] == "schedules":
+		item = s.collection("/schedules").find(identifier)
+	case len(segments) == 3 && segments[0] == "schedules" && segments[1] == "on-calls":
+		item = s.collection("/users").find(identifier)
+	}
+	if item == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No calendar found for [%s]", identifier))
+		return
+	}
+
+	name, _ := item["name"].(string)
+	if name == "" {
+		name, _ = item["username"].(string)
+	}
+	w.Header().Set("Content-Type", "text/calendar")
+	w.Header().Set("X-Request-Id", newId())
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//fakeopsgenie//EN\r\nX-WR-CALNAME:%s\r\nEND:VCALENDAR\r\n", name)
+}
+
+func (s *Server) handleSingleton(w http.ResponseWriter, r *http.Request, key string, body map[string]interface{}) {
+	switch r.Method {
+	case http.MethodGet:
+		if s.actions[key] == nil {
+			writeData(w, http.StatusOK, map[string]interface{}{})
+			return
+		}
+		writeData(w, http.StatusOK, s.actions[key])
+	default:
+		s.actions[key] = body
+		writeData(w, http.StatusOK, body)
+	}
+}
+
+func (s *Server) collection(key string) *collection {
+	c, ok := s.collections[key]
+	if !ok {
+		c = &collection{items: make(map[string]map[string]interface{})}
+		s.collections[key] = c
+	}
+	return c
+}
+
+// create stores item in c with a new id, and adds the resources that the
+// API creates along with the item.
+func (s *Server) create(key string, c *collection, item map[string]interface{}) map[string]interface{} {
+	id := newId()
+	item["id"] = id
+	item["createdAt"] = time.Now().UTC().Format(time.RFC3339)
+	if _, ok := item["enabled"]; !ok {
+		item["enabled"] = true
+	}
+	c.add(id, item)
+	s.log(key, id, "created")
+
+	switch key {
+	case "/integrations":
+		item["apiKey"] = newId()
+	case "/teams":
+		s.createDefaultTeamResources(id, item["name"].(string))
+	case "/schedules":
+		// rotations posted with the schedule are served as its rotations
+		rotations, _ := item["rotations"].([]interface{})
+		delete(item, "rotations")
+		rotationsKey := key + "/" + id + "/rotations"
+		for _, rotation := range rotations {
+			if r, ok := rotation.(map[string]interface{}); ok {
+				s.create(rotationsKey, s.collection(rotationsKey), r)
+			}
+		}
+	}
+
+	return item
+}
+
+// createDefaultTeamResources adds the schedule, escalation and routing rule
+// that OpsGenie creates for every new team.
+func (s *Server) createDefaultTeamResources(teamId, teamName string) {
+	ownerTeam := map[string]interface{}{"id": teamId, "name": teamName}
+
+	schedule := s.create("/schedules", s.collection("/schedules"), map[string]interface{}{
+		"name":      teamName + "_schedule",
+		"timezone":  "America/New_York",
+		"ownerTeam": ownerTeam,
+	})
+	escalation := s.create("/escalations", s.collection("/escalations"), map[string]interface{}{
+		"name":      teamName + "_escalation",
+		"ownerTeam": ownerTeam,
+		"rules": []interface{}{map[string]interface{}{
+			"condition":  "if-not-acked",
+			"notifyType": "default",
+			"delay":      map[string]interface{}{"timeAmount": 0, "timeUnit": "minutes"},
+			"recipient":  map[string]interface{}{"type": "schedule", "id": schedule["id"], "name": schedule["name"]},
+		}},
+	})
+
+	routingRulesKey := "/teams/" + teamId + "/routing-rules"
+	s.create(routingRulesKey, s.collection(routingRulesKey), map[string]interface{}{
+		"name":      "Default Routing Rule",
+		"isDefault": true,
+		"order":     0,
+		"timezone":  "America/New_York",
+		"notify":    map[string]interface{}{"type": "escalation", "id": escalation["id"], "name": escalation["name"]},
+	})
+}
+
+// log records team logs, which are listed by the opsgenie_team_logs data
+// source.
+func (s *Server) log(key, id, action string) {
+	if key != "/teams" {
+		return
+	}
+	s.logs[id] = append(s.logs[id], map[string]interface{}{
+		"owner":       "fakeopsgenie",
+		"createdDate": time.Now().UTC().Format(time.RFC3339),
+		"log":         "Team " + action,
+	})
+}
+
+func (c *collection) add(id string, item map[string]interface{}) {
+	c.ids = append(c.ids, id)
+	c.items[id] = item
+}
+
+func (c *collection) delete(id string) {
+	delete(c.items, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+// find looks up an item by id, name or username, as the API accepts any of
+// them as identifier depending on the identifierType query parameter.
+func (c *collection) find(identifier string) map[string]interface{} {
+	if item, ok := c.items[identifier]; ok {
+		return item
+	}
+	for _, id := range c.ids {
+		item := c.items[id]
+		if item["name"] == identifier || item["username"] == identifier {
+			return item
+		}
+	}
+	return nil
+}
+
+func (c *collection) list(filter map[string]string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(c.ids))
+	for _, id := range c.ids {
+		item := c.items[id]
+		matches := true
+		for k, v := range filter {
+			if item[k] != v {
+				matches = false
+			}
+		}
+		if matches {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func newId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	write(w, status, map[string]interface{}{"data": data})
+}
+
+func writeResult(w http.ResponseWriter, result string, data interface{}) {
+	response := map[string]interface{}{"result": result}
+	if data != nil {
+		response["data"] = data
+	}
+	write(w, http.StatusOK, response)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	write(w, status, map[string]interface{}{"message": message})
+}
+
+func write(w http.ResponseWriter, status int, response map[string]interface{}) {
+	requestId := newId()
+	response["took"] = 0.001
+	response["requestId"] = requestId
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", requestId)
+	w.Header().Set("X-RateLimit-State", "OK")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
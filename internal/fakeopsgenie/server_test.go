@@ -0,0 +1,206 @@
+package fakeopsgenie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/heartbeat"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/policy"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+)
+
+func testConfig(server *Server, apiKey string) *client.Config {
+	return &client.Config{
+		ApiKey:         apiKey,
+		OpsGenieAPIURL: client.ApiUrl(server.ApiUrl()),
+		RetryCount:     1,
+	}
+}
+
+func TestServer_team(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	teamClient, err := team.NewClient(testConfig(server, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := teamClient.Create(ctx, &team.CreateTeamRequest{Name: "genietest-team", Description: "created"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Id == "" {
+		t.Fatalf("Expected the team to get an id")
+	}
+
+	if _, err := teamClient.Create(ctx, &team.CreateTeamRequest{Name: "genietest-team"}); err == nil {
+		t.Fatalf("Expected an error for a duplicate team name")
+	}
+
+	if _, err := teamClient.Update(ctx, &team.UpdateTeamRequest{Id: created.Id, Name: "genietest-team", Description: "updated"}); err != nil {
+		t.Fatal(err)
+	}
+	byName, err := teamClient.Get(ctx, &team.GetTeamRequest{IdentifierType: team.Name, IdentifierValue: "genietest-team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byName.Id != created.Id || byName.Description != "updated" {
+		t.Fatalf("Expected to get the updated team by name, got %+v", byName)
+	}
+
+	logs, err := teamClient.ListTeamLogs(ctx, &team.ListTeamLogsRequest{IdentifierType: team.Id, IdentifierValue: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs.Logs) != 2 {
+		t.Fatalf("Expected a log entry for the creation and the update of the team, got %+v", logs.Logs)
+	}
+
+	routingRules, err := teamClient.ListRoutingRules(ctx, &team.ListRoutingRulesRequest{TeamIdentifierType: team.Id, TeamIdentifierValue: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routingRules.RoutingRules) != 1 || !routingRules.RoutingRules[0].IsDefault {
+		t.Fatalf("Expected the team to have a default routing rule, got %+v", routingRules.RoutingRules)
+	}
+
+	scheduleClient, err := schedule.NewClient(testConfig(server, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultSchedule, err := scheduleClient.Get(ctx, &schedule.GetRequest{IdentifierType: schedule.Name, IdentifierValue: "genietest-team_schedule"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultSchedule.Schedule.OwnerTeam == nil || defaultSchedule.Schedule.OwnerTeam.Id != created.Id {
+		t.Fatalf("Expected the default schedule to be owned by the team, got %+v", defaultSchedule.Schedule)
+	}
+
+	if _, err := teamClient.Delete(ctx, &team.DeleteTeamRequest{IdentifierType: team.Id, IdentifierValue: created.Id}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = teamClient.Get(ctx, &team.GetTeamRequest{IdentifierType: team.Id, IdentifierValue: created.Id})
+	if apiErr, ok := err.(*client.ApiError); !ok || apiErr.StatusCode != 404 {
+		t.Fatalf("Expected a 404 error for a deleted team, got %v", err)
+	}
+}
+
+func TestServer_heartbeat(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	heartbeatClient, err := heartbeat.NewClient(testConfig(server, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := true
+	if _, err := heartbeatClient.Add(ctx, &heartbeat.AddRequest{Name: "genietest-heartbeat", Interval: 10, IntervalUnit: heartbeat.Minutes, Enabled: &enabled}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := heartbeatClient.Disable(ctx, "genietest-heartbeat"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := heartbeatClient.Get(ctx, "genietest-heartbeat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Interval != 10 || result.Enabled {
+		t.Fatalf("Expected a disabled heartbeat with an interval of 10, got %+v", result.Heartbeat)
+	}
+
+	list, err := heartbeatClient.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Heartbeats) != 1 {
+		t.Fatalf("Expected to list one heartbeat, got %+v", list.Heartbeats)
+	}
+}
+
+func TestServer_scheduleRotations(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	scheduleClient, err := schedule.NewClient(testConfig(server, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startDate := time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC)
+	created, err := scheduleClient.Create(ctx, &schedule.CreateRequest{
+		Name: "genietest-schedule",
+		Rotations: []og.Rotation{{
+			Name:         "weekdays",
+			StartDate:    &startDate,
+			Type:         og.Weekly,
+			Participants: []og.Participant{{Type: og.User, Username: "jane@example.com"}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotations, err := scheduleClient.ListRotations(ctx, &schedule.ListRotationsRequest{
+		ScheduleIdentifierType:  schedule.Id,
+		ScheduleIdentifierValue: created.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations.Rotations) != 1 || rotations.Rotations[0].Id == "" || rotations.Rotations[0].Name != "weekdays" {
+		t.Fatalf("Expected the rotation created with the schedule to get an id, got %+v", rotations.Rotations)
+	}
+}
+
+func TestServer_policy(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	policyClient, err := policy.NewClient(testConfig(server, "key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := true
+	created, err := policyClient.CreateAlertPolicy(ctx, &policy.CreateAlertPolicyRequest{
+		MainFields: policy.MainFields{PolicyType: "alert", Name: "genietest-policy", Enabled: &enabled},
+		Message:    "{{message}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := policyClient.ListAlertPolicies(ctx, &policy.ListAlertPoliciesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Policies) != 1 || list.Policies[0].Id != created.Id {
+		t.Fatalf("Expected to list the alert policy, got %+v", list.Policies)
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	server := NewServer("key")
+	defer server.Close()
+
+	teamClient, err := team.NewClient(testConfig(server, "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = teamClient.List(context.Background(), &team.ListTeamRequest{})
+	if apiErr, ok := err.(*client.ApiError); !ok || apiErr.StatusCode != 401 {
+		t.Fatalf("Expected a 401 error for an invalid api key, got %v", err)
+	}
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	// embed the IANA time zone database that time zones are validated
+	// against, as hosts like Windows do not provide one
+	_ "time/tzdata"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/opsgenie/terraform-provider-opsgenie/opsgenie"
 )
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: opsgenie.Provider})
+		GRPCProviderFunc: opsgenie.ProviderServer})
+
+	// Serve returns once Terraform shuts the provider down
+	opsgenie.WriteMetricsSummary()
 }
@@ -1,7 +1,16 @@
 package opsgenie
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
@@ -14,21 +23,202 @@ type OpsgenieClient struct {
 type Config struct {
 	ApiKey string
 	ApiUrl string
+
+	RetryCount       int
+	RetryWaitMin     time.Duration
+	RetryWaitMax     time.Duration
+	RequestTimeout   time.Duration
+	RetryOnRateLimit bool
+
+	ProxyConfiguration *client.ProxyConfiguration
+	CACertFile         string
+	InsecureSkipVerify bool
+
+	MetricsSummary     bool
+	MetricsSummaryFile string
 }
 
 func (c *Config) Client() (*OpsgenieClient, error) {
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	if c.MetricsSummary || c.MetricsSummaryFile != "" {
+		enableMetrics(httpClient, c.MetricsSummaryFile)
+	}
+	enableRequestTracing(httpClient)
+
 	config := &client.Config{
 		ApiKey:         c.ApiKey,
-		RetryCount:     10,
+		RetryCount:     c.RetryCount,
 		OpsGenieAPIURL: client.ApiUrl(c.ApiUrl),
-		Backoff:        retryablehttp.DefaultBackoff,
+		Backoff:        retryAfterBackoff,
+		RetryPolicy:    retryPolicy(c.RetryOnRateLimit),
+		RequestTimeout: c.RequestTimeout,
+		HttpClient:     httpClient,
+		Logger:         newSdkLogger(),
 	}
 	ogCli, err := client.NewOpsGenieClient(config)
 	if err != nil {
 		return nil, err
 	}
+
+	// the SDK falls back to its own retry count when RetryCount is 0,
+	// so set it on the underlying client to allow disabling retries
+	ogCli.RetryableClient.RetryMax = c.RetryCount
+	if c.RetryWaitMin != 0 {
+		ogCli.RetryableClient.RetryWaitMin = c.RetryWaitMin
+	}
+	if c.RetryWaitMax != 0 {
+		ogCli.RetryableClient.RetryWaitMax = c.RetryWaitMax
+	}
+
 	ogClient := OpsgenieClient{}
 	ogClient.client = ogCli
 	log.Printf("[INFO] OpsGenie client configured")
 	return &ogClient, nil
 }
+
+type accountRequest struct {
+	client.BaseRequest
+}
+
+func (r *accountRequest) Validate() error {
+	return nil
+}
+
+func (r *accountRequest) ResourcePath() string {
+	return "/v2/account"
+}
+
+func (r *accountRequest) Method() string {
+	return http.MethodGet
+}
+
+type accountResult struct {
+	client.ResultMetadata
+	Data struct {
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+// validateCredentials reads the account of the API key, so that an invalid
+// key or a key of another region fails when the provider is configured
+// instead of on the first request of a resource.
+func (c *OpsgenieClient) validateCredentials(ctx context.Context) error {
+	apiUrl := c.client.Config.OpsGenieAPIURL
+	result := &accountResult{}
+
+	err := c.client.Exec(ctx, &accountRequest{}, result)
+	if apiErr, ok := err.(*client.ApiError); ok {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusUnprocessableEntity:
+			return fmt.Errorf("the api_key is not valid for %s: %s. Check that the region or api_url matches the region of your OpsGenie account, e.g. region = \"eu\" for accounts hosted in the EU. If the key can manage resources but not read the account, set skip_credentials_validation = true", apiUrl, apiErr.Message)
+		case http.StatusForbidden:
+			return fmt.Errorf("the api_key is not allowed to read the account at %s: %s. The check needs the key of an API integration with configuration access. If the key can manage resources but not read the account, set skip_credentials_validation = true", apiUrl, apiErr.Message)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("could not connect to the OpsGenie API at %s: %s", apiUrl, err)
+	}
+
+	log.Printf("[INFO] OpsGenie API key is valid for account %s at %s", result.Data.Name, apiUrl)
+	return nil
+}
+
+// httpClient builds the HTTP client used by the SDK. The proxy is set on the
+// transport here rather than through the SDK's ProxyConfiguration, which
+// replaces the whole transport and would drop the TLS settings.
+func (c *Config) httpClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.ProxyConfiguration != nil {
+		proxyUrl := proxyURL(c.ProxyConfiguration)
+		log.Printf("[INFO] Using proxy %s://%s for OpsGenie API requests", proxyUrl.Scheme, proxyUrl.Host)
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	if c.CACertFile != "" || c.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		}
+
+		if c.CACertFile != "" {
+			pem, err := ioutil.ReadFile(c.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read ca_cert_file: %s", err)
+			}
+
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("could not find any PEM encoded certificate in ca_cert_file %q", c.CACertFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if c.InsecureSkipVerify {
+			log.Printf("[WARN] TLS certificate verification of the OpsGenie API is disabled")
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+	}, nil
+}
+
+func proxyURL(proxy *client.ProxyConfiguration) *url.URL {
+	host := proxy.Host
+	if proxy.Port != 0 {
+		host = host + ":" + strconv.Itoa(proxy.Port)
+	}
+	proxyUrl := &url.URL{
+		Scheme: string(proxy.Protocol),
+		Host:   host,
+	}
+	if proxy.Username != "" {
+		proxyUrl.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+
+	return proxyUrl
+}
+
+// retryPolicy retries on connection errors and 5xx responses like the SDK
+// default policy. 429 responses are only retried when retryOnRateLimit is set.
+func retryPolicy(retryOnRateLimit bool) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if err != nil {
+			return true, err
+		}
+
+		if resp.StatusCode == 0 || (resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented) {
+			return true, nil
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return retryOnRateLimit, nil
+		}
+
+		return false, nil
+	}
+}
+
+// retryAfterBackoff waits for the duration of the Retry-After header of
+// rate limited responses, and backs off exponentially otherwise.
+func retryAfterBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+}
@@ -0,0 +1,174 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
+)
+
+// integrationActionTypes are the blocks of opsgenie_integration_action, each
+// holding a list of actions with a filter.
+var integrationActionTypes = []string{"create", "close", "acknowledge", "add_note", "ignore"}
+
+// planValues is the part of schema.ResourceDiff used by the plan-time
+// validations, so that they can be tested without a plan.
+type planValues interface {
+	Get(key string) interface{}
+	NewValueKnown(key string) bool
+}
+
+// customizeDiffFilter validates the filter or criteria blocks at key during
+// plan, like the SDK validates them before sending them to the API.
+func customizeDiffFilter(key string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		return validateFilterPlan(d, key)
+	}
+}
+
+// customizeDiffTimeRestriction validates the time_restriction blocks at key
+// during plan.
+func customizeDiffTimeRestriction(key string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		return validateTimeRestrictionPlan(d, key)
+	}
+}
+
+func customizeDiffIntegrationActionFilters(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, actionType := range integrationActionTypes {
+		if !d.NewValueKnown(actionType) {
+			continue
+		}
+		for i := range d.Get(actionType).([]interface{}) {
+			if err := validateFilterPlan(d, fmt.Sprintf("%s.%d.filter", actionType, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateFilterPlan(d planValues, key string) error {
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+
+	for i := range d.Get(key).([]interface{}) {
+		path := fmt.Sprintf("%s.%d", key, i)
+		if !d.NewValueKnown(path+".type") || !d.NewValueKnown(path+".conditions") {
+			continue
+		}
+
+		matchType := d.Get(path + ".type").(string)
+		conditions := d.Get(path + ".conditions").([]interface{})
+		switch og.ConditionMatchType(matchType) {
+		case og.MatchAll:
+			if len(conditions) > 0 {
+				return fmt.Errorf("%s.conditions: conditions cannot be set when type is %s, use match-all-conditions or match-any-condition", path, matchType)
+			}
+		case og.MatchAllConditions, og.MatchAnyCondition:
+			if len(conditions) == 0 {
+				return fmt.Errorf("%s.conditions: at least one condition is required when type is %s", path, matchType)
+			}
+		}
+
+		for j := range conditions {
+			conditionPath := fmt.Sprintf("%s.conditions.%d", path, j)
+			if !valuesKnown(d, conditionPath, "field", "operation", "key", "expected_value") {
+				continue
+			}
+			condition := d.Get(conditionPath).(map[string]interface{})
+			if err := og.ValidateConditions([]og.Condition{planCondition(condition)}); err != nil {
+				return fmt.Errorf("%s: %s", conditionPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+// valuesKnown returns whether the fields of the block at path are all known.
+// Unknown fields of a known block read as their zero value, so the block
+// itself being known is not enough.
+func valuesKnown(d planValues, path string, fields ...string) bool {
+	if !d.NewValueKnown(path) {
+		return false
+	}
+	for _, field := range fields {
+		if !d.NewValueKnown(path + "." + field) {
+			return false
+		}
+	}
+	return true
+}
+
+func planCondition(condition map[string]interface{}) og.Condition {
+	return og.Condition{
+		Field:         og.ConditionFieldType(condition["field"].(string)),
+		Operation:     og.ConditionOperation(condition["operation"].(string)),
+		Key:           condition["key"].(string),
+		ExpectedValue: condition["expected_value"].(string),
+	}
+}
+
+func validateTimeRestrictionPlan(d planValues, key string) error {
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+
+	for i := range d.Get(key).([]interface{}) {
+		path := fmt.Sprintf("%s.%d", key, i)
+		if !d.NewValueKnown(path+".type") || !d.NewValueKnown(path+".restrictions") || !d.NewValueKnown(path+".restriction") {
+			continue
+		}
+
+		restrictionType := d.Get(path + ".type").(string)
+		restrictions := d.Get(path + ".restrictions").([]interface{})
+		restriction := d.Get(path + ".restriction").([]interface{})
+		switch og.RestrictionType(restrictionType) {
+		case og.TimeOfDay:
+			if len(restrictions) > 0 {
+				return fmt.Errorf("%s.restrictions: restrictions cannot be set when type is %s, use restriction", path, restrictionType)
+			}
+			if len(restriction) != 1 {
+				return fmt.Errorf("%s.restriction: exactly one restriction is required when type is %s", path, restrictionType)
+			}
+			if err := validateRestrictionHours(d, path+".restriction.0"); err != nil {
+				return err
+			}
+		case og.WeekdayAndTimeOfDay:
+			if len(restriction) > 0 {
+				return fmt.Errorf("%s.restriction: restriction cannot be set when type is %s, use restrictions", path, restrictionType)
+			}
+			if len(restrictions) == 0 {
+				return fmt.Errorf("%s.restrictions: at least one restriction is required when type is %s", path, restrictionType)
+			}
+			for j := range restrictions {
+				if err := validateRestrictionHours(d, fmt.Sprintf("%s.restrictions.%d", path, j)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateRestrictionHours(d planValues, path string) error {
+	for _, field := range []string{"start_hour", "end_hour"} {
+		if !d.NewValueKnown(path + "." + field) {
+			continue
+		}
+		if hour := d.Get(path + "." + field).(int); hour < 0 || hour > 24 {
+			return fmt.Errorf("%s.%s: must be between 0 and 24, got %d", path, field, hour)
+		}
+	}
+	for _, field := range []string{"start_min", "end_min"} {
+		if !d.NewValueKnown(path + "." + field) {
+			continue
+		}
+		if min := d.Get(path + "." + field).(int); min < 0 || min > 59 {
+			return fmt.Errorf("%s.%s: must be between 0 and 59, got %d", path, field, min)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package opsgenie
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// unknown is how the SDK represents values that are only known after apply in
+// the raw configuration of a plan.
+const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// knownPlanValues adapts ResourceData to planValues, with all values known.
+type knownPlanValues struct {
+	*schema.ResourceData
+}
+
+func (d knownPlanValues) NewValueKnown(key string) bool {
+	return true
+}
+
+func TestValidateFilterPlan(t *testing.T) {
+	cases := []struct {
+		filter   map[string]interface{}
+		expected string
+	}{
+		{
+			filter: map[string]interface{}{"type": "match-all"},
+		},
+		{
+			filter: map[string]interface{}{
+				"type":       "match-any-condition",
+				"conditions": []interface{}{map[string]interface{}{"field": "message", "operation": "contains", "expected_value": "db"}},
+			},
+		},
+		{
+			filter: map[string]interface{}{
+				"type":       "match-all",
+				"conditions": []interface{}{map[string]interface{}{"field": "message", "operation": "contains", "expected_value": "db"}},
+			},
+			expected: "filter.0.conditions: conditions cannot be set when type is match-all",
+		},
+		{
+			filter:   map[string]interface{}{"type": "match-any-condition"},
+			expected: "filter.0.conditions: at least one condition is required when type is match-any-condition",
+		},
+		{
+			filter: map[string]interface{}{
+				"type": "match-all-conditions",
+				"conditions": []interface{}{
+					map[string]interface{}{"field": "message", "operation": "contains", "expected_value": "db"},
+					map[string]interface{}{"field": "priority", "operation": "equals", "expected_value": "P6"},
+				},
+			},
+			expected: "filter.0.conditions.1: for field priority expected value should be one of P1, P2, P3, P4, P5",
+		},
+		{
+			filter: map[string]interface{}{
+				"type":       "match-all-conditions",
+				"conditions": []interface{}{map[string]interface{}{"field": "message", "operation": "contains", "key": "host"}},
+			},
+			expected: "filter.0.conditions.0: condition key is only valid for extra-properties field",
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceOpsGenieAlertPolicy().Schema, map[string]interface{}{
+			"name":    "policy",
+			"message": "{{message}}",
+			"filter":  []interface{}{c.filter},
+		})
+		err := validateFilterPlan(knownPlanValues{d}, "filter")
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.filter, err)
+		}
+		if c.expected != "" && (err == nil || !strings.HasPrefix(err.Error(), c.expected)) {
+			t.Errorf("Expected %q for %v, got %v", c.expected, c.filter, err)
+		}
+	}
+}
+
+func TestValidateFilterPlan_unknownConditionFields(t *testing.T) {
+	conditions := []map[string]interface{}{
+		{"field": "priority", "operation": "equals", "expected_value": unknown},
+		{"field": "priority", "operation": unknown, "expected_value": "P1"},
+		{"field": unknown, "operation": "equals", "expected_value": "P1"},
+		{"field": "extra-properties", "operation": "equals", "key": unknown, "expected_value": "prod"},
+	}
+	for _, condition := range conditions {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "policy",
+			"message": "{{message}}",
+			"filter": []interface{}{map[string]interface{}{
+				"type":       "match-all-conditions",
+				"conditions": []interface{}{condition},
+			}},
+		})
+		if _, err := resourceOpsGenieAlertPolicy().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil); err != nil {
+			t.Errorf("Expected %v to be valid until its values are known, got %s", condition, err)
+		}
+	}
+}
+
+func TestValidateTimeRestrictionPlan(t *testing.T) {
+	restriction := map[string]interface{}{"start_hour": 8, "start_min": 0, "end_hour": 18, "end_min": 30}
+	restrictions := map[string]interface{}{"start_day": "monday", "end_day": "friday", "start_hour": 8, "start_min": 0, "end_hour": 18, "end_min": 30}
+
+	cases := []struct {
+		timeRestriction map[string]interface{}
+		expected        string
+	}{
+		{
+			timeRestriction: map[string]interface{}{"type": "time-of-day", "restriction": []interface{}{restriction}},
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "weekday-and-time-of-day", "restrictions": []interface{}{restrictions, restrictions}},
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "time-of-day", "restrictions": []interface{}{restrictions}},
+			expected:        "time_restriction.0.restrictions: restrictions cannot be set when type is time-of-day, use restriction",
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "time-of-day"},
+			expected:        "time_restriction.0.restriction: exactly one restriction is required when type is time-of-day",
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "weekday-and-time-of-day", "restriction": []interface{}{restriction}},
+			expected:        "time_restriction.0.restriction: restriction cannot be set when type is weekday-and-time-of-day, use restrictions",
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "weekday-and-time-of-day"},
+			expected:        "time_restriction.0.restrictions: at least one restriction is required when type is weekday-and-time-of-day",
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "time-of-day", "restriction": []interface{}{
+				map[string]interface{}{"start_hour": 8, "start_min": 0, "end_hour": 25, "end_min": 0},
+			}},
+			expected: "time_restriction.0.restriction.0.end_hour: must be between 0 and 24, got 25",
+		},
+		{
+			timeRestriction: map[string]interface{}{"type": "weekday-and-time-of-day", "restrictions": []interface{}{
+				restrictions,
+				map[string]interface{}{"start_day": "monday", "end_day": "friday", "start_hour": 8, "start_min": 60, "end_hour": 18, "end_min": 0},
+			}},
+			expected: "time_restriction.0.restrictions.1.start_min: must be between 0 and 59, got 60",
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceOpsGenieTeamRoutingRule().Schema, map[string]interface{}{
+			"name":             "rule",
+			"team_id":          "team",
+			"time_restriction": []interface{}{c.timeRestriction},
+		})
+		err := validateTimeRestrictionPlan(knownPlanValues{d}, "time_restriction")
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.timeRestriction, err)
+		}
+		if c.expected != "" && (err == nil || err.Error() != c.expected) {
+			t.Errorf("Expected %q for %v, got %v", c.expected, c.timeRestriction, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
+)
+
+func dataSourceOpsgenieEscalationPath() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOpsgenieEscalationPathRead,
+		Schema: map[string]*schema.Schema{
+			"escalation_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Time the alert is created at, in RFC3339 format. Defaults to the time of the read",
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"first_user_minute": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Minute of the first notification that is resolved to a user, or -1 if no user is notified",
+			},
+			"notification": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"minute": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"repeat": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"condition": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"notify_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"recipient_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"recipient_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"recipient_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOpsgenieEscalationPathRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	at := time.Now().UTC()
+	if v := d.Get("at").(string); v != "" {
+		at, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	result, err := client.Get(ctx, &escalation.GetRequest{
+		IdentifierType: escalation.Id,
+		Identifier:     d.Get("escalation_id").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resolver := &escalationRecipientResolver{config: meta.(*OpsgenieClient), cache: make(map[string][]string)}
+	notifications, err := escalationPath(ctx, result.Rules, result.Repeat, at, resolver.resolve)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	firstUserMinute := -1
+	for _, n := range notifications {
+		if n["username"] != "" {
+			firstUserMinute = n["minute"].(int)
+			break
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", result.Id, at.Format(time.RFC3339)))
+	d.Set("first_user_minute", firstUserMinute)
+	d.Set("notification", notifications)
+
+	return nil
+}
+
+// escalationPath lists the notifications that an alert created at start
+// triggers if it is never acknowledged, in the order they are sent. A rule
+// notifies each user that resolve returns for it, or its recipient without a
+// username if no user can be resolved. The escalation repeats repeat.Count
+// times, each time repeat.WaitInterval minutes after the rule with the
+// longest delay.
+func escalationPath(ctx context.Context, rules []escalation.Rule, repeat escalation.Repeat, start time.Time, resolve func(context.Context, escalation.Rule, time.Time) ([]string, error)) ([]map[string]interface{}, error) {
+	sorted := make([]escalation.Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return escalationDelayMinutes(sorted[i].Delay) < escalationDelayMinutes(sorted[j].Delay)
+	})
+
+	cycle := int(repeat.WaitInterval)
+	if len(sorted) > 0 {
+		cycle += escalationDelayMinutes(sorted[len(sorted)-1].Delay)
+	}
+
+	notifications := make([]map[string]interface{}, 0, len(sorted))
+	for r := 0; r <= int(repeat.Count); r++ {
+		for _, rule := range sorted {
+			minute := r*cycle + escalationDelayMinutes(rule.Delay)
+			at := start.Add(time.Duration(minute) * time.Minute)
+
+			usernames, err := resolve(ctx, rule, at)
+			if err != nil {
+				return nil, err
+			}
+			if len(usernames) == 0 {
+				usernames = []string{""}
+			}
+
+			for _, username := range usernames {
+				notifications = append(notifications, map[string]interface{}{
+					"minute":         minute,
+					"time":           at.Format(time.RFC3339),
+					"repeat":         r,
+					"condition":      string(rule.Condition),
+					"notify_type":    string(rule.NotifyType),
+					"recipient_type": string(rule.Recipient.Type),
+					"recipient_id":   rule.Recipient.Id,
+					"recipient_name": rule.Recipient.Name,
+					"username":       username,
+				})
+			}
+		}
+	}
+
+	return notifications, nil
+}
+
+func escalationDelayMinutes(delay escalation.EscalationDelay) int {
+	switch delay.TimeUnit {
+	case og.Hours:
+		return int(delay.TimeAmount) * 60
+	case og.Days:
+		return int(delay.TimeAmount) * 24 * 60
+	default:
+		return int(delay.TimeAmount)
+	}
+}
+
+// escalationRecipientResolver resolves the recipients of escalation rules to
+// the usernames of the users they notify, caching the API calls.
+type escalationRecipientResolver struct {
+	config *OpsgenieClient
+	cache  map[string][]string
+}
+
+func (r *escalationRecipientResolver) resolve(ctx context.Context, rule escalation.Rule, at time.Time) ([]string, error) {
+	recipient := rule.Recipient
+	notifyType := strings.ToLower(string(rule.NotifyType))
+
+	key := fmt.Sprintf("%s/%s/%s", recipient.Type, recipient.Id, notifyType)
+	if recipient.Type == og.Schedule {
+		key += "/" + at.Format(time.RFC3339)
+	}
+	if usernames, ok := r.cache[key]; ok {
+		return usernames, nil
+	}
+
+	var usernames []string
+	var err error
+	switch recipient.Type {
+	case og.User:
+		usernames, err = r.resolveUser(ctx, recipient)
+	case og.Schedule:
+		usernames, err = r.resolveSchedule(ctx, recipient, notifyType, at)
+	case og.Team:
+		usernames, err = r.resolveTeam(ctx, recipient, notifyType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache[key] = usernames
+	return usernames, nil
+}
+
+func (r *escalationRecipientResolver) resolveUser(ctx context.Context, recipient og.Participant) ([]string, error) {
+	if recipient.Username != "" {
+		return []string{recipient.Username}, nil
+	}
+
+	client, err := user.NewClient(r.config.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &user.GetRequest{Identifier: recipient.Id})
+	if err != nil {
+		return nil, err
+	}
+	return []string{result.Username}, nil
+}
+
+// resolveSchedule returns the users on call at the given time. The previous
+// on-call users cannot be looked up with the API, so they are not resolved.
+func (r *escalationRecipientResolver) resolveSchedule(ctx context.Context, recipient og.Participant, notifyType string, at time.Time) ([]string, error) {
+	client, err := schedule.NewClient(r.config.client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := true
+	date := at.UTC()
+	switch notifyType {
+	case "next":
+		result, err := client.GetNextOnCall(ctx, &schedule.GetNextOnCallsRequest{
+			Flat:                   &flat,
+			Date:                   &date,
+			ScheduleIdentifierType: schedule.Id,
+			ScheduleIdentifier:     recipient.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.NextOncallParticipants, nil
+	case "previous":
+		return nil, nil
+	default:
+		result, err := client.GetOnCalls(ctx, &schedule.GetOnCallsRequest{
+			Flat:                   &flat,
+			Date:                   &date,
+			ScheduleIdentifierType: schedule.Id,
+			ScheduleIdentifier:     recipient.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.OnCallRecipients, nil
+	}
+}
+
+// resolveTeam returns the members of the team that the notify type selects.
+// The default notify type routes the alert with the routing rules of the
+// team, which are not evaluated, so it is not resolved. All members are
+// returned for random, as any of them may be notified.
+func (r *escalationRecipientResolver) resolveTeam(ctx context.Context, recipient og.Participant, notifyType string) ([]string, error) {
+	if notifyType == "default" {
+		return nil, nil
+	}
+
+	client, err := team.NewClient(r.config.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &team.GetTeamRequest{
+		IdentifierType:  team.Id,
+		IdentifierValue: recipient.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var usernames []string
+	for _, member := range result.Members {
+		switch {
+		case notifyType == "admins" && member.Role != "admin":
+			continue
+		case notifyType == "users" && member.Role == "admin":
+			continue
+		}
+		usernames = append(usernames, member.User.Username)
+	}
+	return usernames, nil
+}
@@ -0,0 +1,119 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
+)
+
+func TestAccDataSourceOpsGenieEscalationPath_Basic(t *testing.T) {
+	randomUser := acctest.RandString(6)
+	randomEscalation := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOpsGenieEscalationPathConfig(randomUser, randomEscalation),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "first_user_minute", "0"),
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "notification.#", "2"),
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "notification.0.username", fmt.Sprintf("genietest-%s@opsgenie.com", randomUser)),
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "notification.0.time", "2021-03-01T09:00:00Z"),
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "notification.1.minute", "15"),
+					resource.TestCheckResourceAttr("data.opsgenie_escalation_path.test", "notification.1.repeat", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceOpsGenieEscalationPathConfig(randomUser, randomEscalation string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_user" "test" {
+  username  = "genietest-%s@opsgenie.com"
+  full_name = "Acceptance Test User"
+  role      = "User"
+}
+resource "opsgenie_escalation" "test" {
+  name = "genieescalation-%s"
+  rules {
+    condition   = "if-not-acked"
+    notify_type = "default"
+    recipient {
+      type = "user"
+      id   = opsgenie_user.test.id
+    }
+    delay = 0
+  }
+  repeat {
+    wait_interval = 15
+    count         = 1
+  }
+}
+data "opsgenie_escalation_path" "test" {
+  escalation_id = opsgenie_escalation.test.id
+  at            = "2021-03-01T09:00:00Z"
+}
+`, randomUser, randomEscalation)
+}
+
+func TestEscalationPath(t *testing.T) {
+	rules := []escalation.Rule{
+		{
+			Condition:  og.IfNotAcked,
+			NotifyType: og.Default,
+			Recipient:  og.Participant{Type: og.Team, Id: "team-id", Name: "platform"},
+			Delay:      escalation.EscalationDelay{TimeAmount: 1, TimeUnit: og.Hours},
+		},
+		{
+			Condition:  og.IfNotAcked,
+			NotifyType: og.Default,
+			Recipient:  og.Participant{Type: og.Schedule, Id: "schedule-id", Name: "platform_schedule"},
+			Delay:      escalation.EscalationDelay{TimeAmount: 0, TimeUnit: og.Minutes},
+		},
+	}
+	repeat := escalation.Repeat{WaitInterval: 10, Count: 1}
+	start := time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	resolve := func(ctx context.Context, rule escalation.Rule, at time.Time) ([]string, error) {
+		if rule.Recipient.Type == og.Schedule {
+			return []string{"jane@example.com", "john@example.com"}, nil
+		}
+		return nil, nil
+	}
+
+	notifications, err := escalationPath(context.Background(), rules, repeat, start, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		minute   int
+		time     string
+		repeat   int
+		username string
+	}{
+		{0, "2021-03-01T09:00:00Z", 0, "jane@example.com"},
+		{0, "2021-03-01T09:00:00Z", 0, "john@example.com"},
+		{60, "2021-03-01T10:00:00Z", 0, ""},
+		{70, "2021-03-01T10:10:00Z", 1, "jane@example.com"},
+		{70, "2021-03-01T10:10:00Z", 1, "john@example.com"},
+		{130, "2021-03-01T11:10:00Z", 1, ""},
+	}
+	if len(notifications) != len(expected) {
+		t.Fatalf("Expected %d notifications, got %v", len(expected), notifications)
+	}
+	for i, e := range expected {
+		n := notifications[i]
+		if n["minute"] != e.minute || n["time"] != e.time || n["repeat"] != e.repeat || n["username"] != e.username {
+			t.Errorf("Expected notification %d to be %+v, got %v", i, e, n)
+		}
+	}
+}
@@ -2,15 +2,19 @@ package opsgenie
 
 import (
 	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/heartbeat"
 )
 
 func dataSourceOpsgenieHeartbeat() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceOpsgenieHeartbeatRead,
+		ReadContext: dataSourceOpsgenieHeartbeatRead,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -54,21 +58,26 @@ func dataSourceOpsgenieHeartbeat() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"expired": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"last_ping_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time of the last ping, empty if the heartbeat was never pinged",
+			},
 		},
 	}
 }
 
-func dataSourceOpsgenieHeartbeatRead(d *schema.ResourceData, meta interface{}) error {
-	client, err := heartbeat.NewClient(meta.(*OpsgenieClient).client.Config)
-	if err != nil {
-		return err
-	}
-
+func dataSourceOpsgenieHeartbeatRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	heartbeatName := d.Get("name").(string)
 
-	result, err := client.Get(context.Background(), heartbeatName)
+	result := &heartbeatStatusResult{}
+	err := meta.(*OpsgenieClient).client.Exec(ctx, &heartbeatStatusRequest{name: heartbeatName}, result)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Name)
@@ -81,6 +90,47 @@ func dataSourceOpsgenieHeartbeatRead(d *schema.ResourceData, meta interface{}) e
 	err = d.Set("alert_priority", result.AlertPriority)
 	err = d.Set("alert_tags", result.AlertTags)
 	err = d.Set("alert_message", result.AlertMessage)
+	err = d.Set("expired", result.Expired)
+	err = d.Set("last_ping_time", result.LastPingTime)
 
 	return nil
 }
+
+// heartbeatStatus is a heartbeat with the time of its last ping, which the
+// API returns but heartbeat.Heartbeat does not decode.
+type heartbeatStatus struct {
+	heartbeat.Heartbeat
+	LastPingTime string `json:"lastPingTime"`
+}
+
+// heartbeatStatusRequest gets the heartbeat with the given name, or lists
+// all heartbeats if name is empty.
+type heartbeatStatusRequest struct {
+	client.BaseRequest
+	name string
+}
+
+func (r *heartbeatStatusRequest) Validate() error {
+	return nil
+}
+
+func (r *heartbeatStatusRequest) ResourcePath() string {
+	if r.name == "" {
+		return "/v2/heartbeats"
+	}
+	return "/v2/heartbeats/" + r.name
+}
+
+func (r *heartbeatStatusRequest) Method() string {
+	return http.MethodGet
+}
+
+type heartbeatStatusResult struct {
+	client.ResultMetadata
+	heartbeatStatus
+}
+
+type heartbeatStatusListResult struct {
+	client.ResultMetadata
+	Heartbeats []heartbeatStatus `json:"heartbeats"`
+}
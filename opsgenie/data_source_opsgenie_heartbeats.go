@@ -0,0 +1,164 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceOpsgenieHeartbeats() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOpsgenieHeartbeatsRead,
+		Schema: map[string]*schema.Schema{
+			"owner_team_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the heartbeats owned by this team",
+			},
+			"expired": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return the heartbeats that are expired, or not expired if false",
+			},
+			"last_ping_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only return the heartbeats that were not pinged since this time, in RFC3339 format, including the ones never pinged",
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"heartbeats": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"interval": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"interval_unit": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"expired": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"last_ping_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner_team_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOpsgenieHeartbeatsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	result := &heartbeatStatusListResult{}
+	err := meta.(*OpsgenieClient).client.Exec(ctx, &heartbeatStatusRequest{}, result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := heartbeatFilter{ownerTeamId: d.Get("owner_team_id").(string)}
+	if v, ok := d.GetOkExists("expired"); ok {
+		expired := v.(bool)
+		filter.expired = &expired
+	}
+	if v := d.Get("last_ping_before").(string); v != "" {
+		filter.lastPingBefore, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	heartbeats, err := filterHeartbeats(result.Heartbeats, filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	names := make([]string, 0, len(heartbeats))
+	flattened := make([]map[string]interface{}, 0, len(heartbeats))
+	for _, h := range heartbeats {
+		names = append(names, h.Name)
+		flattened = append(flattened, map[string]interface{}{
+			"name":           h.Name,
+			"description":    h.Description,
+			"interval":       h.Interval,
+			"interval_unit":  h.IntervalUnit,
+			"enabled":        h.Enabled,
+			"expired":        h.Expired,
+			"last_ping_time": h.LastPingTime,
+			"owner_team_id":  h.OwnerTeam.Id,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%d", schema.HashString(strings.Join(names, ","))))
+	d.Set("names", names)
+	d.Set("heartbeats", flattened)
+
+	return nil
+}
+
+type heartbeatFilter struct {
+	ownerTeamId    string
+	expired        *bool
+	lastPingBefore time.Time
+}
+
+// filterHeartbeats returns the heartbeats that match all the criteria of the
+// filter, sorted by name.
+func filterHeartbeats(heartbeats []heartbeatStatus, filter heartbeatFilter) ([]heartbeatStatus, error) {
+	matched := make([]heartbeatStatus, 0, len(heartbeats))
+	for _, h := range heartbeats {
+		if filter.ownerTeamId != "" && h.OwnerTeam.Id != filter.ownerTeamId {
+			continue
+		}
+		if filter.expired != nil && h.Expired != *filter.expired {
+			continue
+		}
+		if !filter.lastPingBefore.IsZero() && h.LastPingTime != "" {
+			lastPing, err := time.Parse(time.RFC3339, h.LastPingTime)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse the last ping time of heartbeat %s: %s", h.Name, err)
+			}
+			if !lastPing.Before(filter.lastPingBefore) {
+				continue
+			}
+		}
+		matched = append(matched, h)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Name < matched[j].Name
+	})
+	return matched, nil
+}
@@ -0,0 +1,143 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/heartbeat"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
+)
+
+func TestAccDataSourceOpsGenieHeartbeats_Basic(t *testing.T) {
+	randomName := acctest.RandString(6)
+	randomTeamName := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOpsGenieHeartbeatsConfig(randomTeamName, randomName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.opsgenie_heartbeats.team", "names.#", "1"),
+					resource.TestCheckResourceAttrPair("data.opsgenie_heartbeats.team", "names.0", "opsgenie_heartbeat.test", "name"),
+					resource.TestCheckResourceAttr("data.opsgenie_heartbeats.team", "heartbeats.0.last_ping_time", ""),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceOpsGenieHeartbeatsConfig(randomTeamName, randomName string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_team" "test" {
+  name        = "genieteam-%s"
+  description = "This team deals with all the things"
+}
+resource "opsgenie_heartbeat" "test" {
+  name          = "genieheartbeat-%s"
+  interval_unit = "minutes"
+  interval      = 10
+  enabled       = false
+  owner_team_id = "${opsgenie_team.test.id}"
+}
+data "opsgenie_heartbeats" "team" {
+  owner_team_id = "${opsgenie_team.test.id}"
+  depends_on    = [opsgenie_heartbeat.test]
+}
+`, randomTeamName, randomName)
+}
+
+func TestHeartbeatStatusRequest(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	config := &client.Config{ApiKey: "key", OpsGenieAPIURL: client.ApiUrl(server.ApiUrl()), RetryCount: 1}
+	heartbeatClient, err := heartbeat.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := true
+	for _, name := range []string{"pinged", "silent"} {
+		if _, err := heartbeatClient.Add(ctx, &heartbeat.AddRequest{Name: name, Interval: 10, IntervalUnit: heartbeat.Minutes, Enabled: &enabled}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := heartbeatClient.Ping(ctx, "pinged"); err != nil {
+		t.Fatal(err)
+	}
+
+	ogClient, err := client.NewOpsGenieClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := &heartbeatStatusResult{}
+	if err := ogClient.Exec(ctx, &heartbeatStatusRequest{name: "pinged"}, result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "pinged" || result.LastPingTime == "" {
+		t.Errorf("Expected the pinged heartbeat with a last ping time, got %+v", result.heartbeatStatus)
+	}
+
+	list := &heartbeatStatusListResult{}
+	if err := ogClient.Exec(ctx, &heartbeatStatusRequest{}, list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Heartbeats) != 2 {
+		t.Fatalf("Expected 2 heartbeats, got %+v", list.Heartbeats)
+	}
+	for _, h := range list.Heartbeats {
+		if (h.LastPingTime != "") != (h.Name == "pinged") {
+			t.Errorf("Expected only the pinged heartbeat to have a last ping time, got %+v", h)
+		}
+	}
+}
+
+func TestFilterHeartbeats(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	heartbeats := []heartbeatStatus{
+		{Heartbeat: heartbeat.Heartbeat{Name: "recent", OwnerTeam: og.OwnerTeam{Id: "team"}}, LastPingTime: now.Add(-time.Hour).Format(time.RFC3339)},
+		{Heartbeat: heartbeat.Heartbeat{Name: "stale", Expired: true}, LastPingTime: "2021-02-01T12:00:00.123Z"},
+		{Heartbeat: heartbeat.Heartbeat{Name: "never", OwnerTeam: og.OwnerTeam{Id: "team"}}},
+	}
+	expired := true
+	notExpired := false
+
+	cases := []struct {
+		name     string
+		filter   heartbeatFilter
+		expected []string
+	}{
+		{"none", heartbeatFilter{}, []string{"never", "recent", "stale"}},
+		{"owner team", heartbeatFilter{ownerTeamId: "team"}, []string{"never", "recent"}},
+		{"expired", heartbeatFilter{expired: &expired}, []string{"stale"}},
+		{"not expired", heartbeatFilter{expired: &notExpired}, []string{"never", "recent"}},
+		{"last ping before", heartbeatFilter{lastPingBefore: now.AddDate(0, 0, -7)}, []string{"never", "stale"}},
+		{"owner team and last ping before", heartbeatFilter{ownerTeamId: "team", lastPingBefore: now.AddDate(0, 0, -7)}, []string{"never"}},
+	}
+	for _, c := range cases {
+		matched, err := filterHeartbeats(heartbeats, c.filter)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		names := make([]string, 0, len(matched))
+		for _, h := range matched {
+			names = append(names, h.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, names)
+		}
+	}
+
+	invalid := []heartbeatStatus{{Heartbeat: heartbeat.Heartbeat{Name: "invalid"}, LastPingTime: "yesterday"}}
+	if _, err := filterHeartbeats(invalid, heartbeatFilter{lastPingBefore: now}); err == nil {
+		t.Error("Expected an error for an invalid last ping time")
+	}
+}
@@ -0,0 +1,92 @@
+package opsgenie
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+)
+
+func dataSourceOpsgenieScheduleIcal() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOpsgenieScheduleIcalRead,
+		Schema: map[string]*schema.Schema{
+			"schedule_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Id of the schedule to export",
+				ExactlyOneOf: []string{"schedule_id", "user"},
+			},
+			"user": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Id or username of the user to export the on-call periods of",
+				ExactlyOneOf: []string{"schedule_id", "user"},
+			},
+			"output_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file to write the calendar to",
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceOpsgenieScheduleIcalRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the SDK writes the calendar to a file in the given directory
+	dir, err := ioutil.TempDir("", "opsgenie-ical")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer os.RemoveAll(dir)
+	exportPath := dir + string(filepath.Separator)
+
+	var file *os.File
+	if scheduleId := d.Get("schedule_id").(string); scheduleId != "" {
+		file, err = client.ExportSchedule(ctx, &schedule.ExportScheduleRequest{
+			IdentifierType:   schedule.Id,
+			IdentifierValue:  scheduleId,
+			ExportedFilePath: exportPath,
+		})
+	} else {
+		file, err = client.ExportOnCallUser(ctx, &schedule.ExportOnCallUserRequest{
+			UserIdentifier:   d.Get("user").(string),
+			ExportedFilePath: exportPath,
+		})
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	content, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if outputPath := d.Get("output_path").(string); outputPath != "" {
+		if err := ioutil.WriteFile(outputPath, content, 0644); err != nil {
+			return diag.Errorf("could not write the calendar to %s: %s", outputPath, err)
+		}
+	}
+
+	checksum := sha1.Sum(content)
+	d.SetId(hex.EncodeToString(checksum[:]))
+	d.Set("content", string(content))
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
+)
+
+func TestAccDataSourceOpsGenieScheduleIcal_Basic(t *testing.T) {
+	randomName := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOpsGenieScheduleIcalConfig(randomName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.opsgenie_schedule_ical.test", "content", regexp.MustCompile("^BEGIN:VCALENDAR")),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceOpsGenieScheduleIcalConfig(randomName string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_schedule" "test" {
+  name     = "genieschedule-%s"
+  timezone = "Europe/Rome"
+  enabled  = false
+}
+data "opsgenie_schedule_ical" "test" {
+  schedule_id = "${opsgenie_schedule.test.id}"
+}
+`, randomName)
+}
+
+func TestDataSourceOpsgenieScheduleIcalRead(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	config := &client.Config{ApiKey: "key", OpsGenieAPIURL: client.ApiUrl(server.ApiUrl()), RetryCount: 1}
+	scheduleClient, err := schedule.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := scheduleClient.Create(ctx, &schedule.CreateRequest{Name: "platform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClient, err := user.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := userClient.Create(ctx, &user.CreateRequest{Username: "jane@example.com", FullName: "Jane", Role: &user.UserRoleRequest{RoleName: "User"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "opsgenie-ical-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "platform.ics")
+	cases := []struct {
+		raw      map[string]interface{}
+		calendar string
+	}{
+		{map[string]interface{}{"schedule_id": created.Id, "output_path": outputPath}, "platform"},
+		{map[string]interface{}{"user": "jane@example.com"}, "jane@example.com"},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceOpsgenieScheduleIcal().Schema, c.raw)
+		if diags := dataSourceOpsgenieScheduleIcalRead(ctx, d, meta); diags.HasError() {
+			t.Fatalf("%v: %v", c.raw, diags)
+		}
+
+		content := d.Get("content").(string)
+		if !strings.HasPrefix(content, "BEGIN:VCALENDAR") || !strings.Contains(content, "X-WR-CALNAME:"+c.calendar) {
+			t.Errorf("%v: expected the calendar of %s, got %q", c.raw, c.calendar, content)
+		}
+		if d.Id() == "" {
+			t.Errorf("%v: expected an id", c.raw)
+		}
+	}
+
+	written, err := ioutil.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "X-WR-CALNAME:platform") {
+		t.Errorf("Expected the schedule calendar to be written to %s, got %q", outputPath, written)
+	}
+}
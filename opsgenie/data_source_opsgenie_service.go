@@ -2,6 +2,7 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/service"
 	"log"
@@ -12,7 +13,7 @@ import (
 
 func dataSourceOpsGenieService() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceOpsGenieServiceRead,
+		ReadContext: dataSourceOpsGenieServiceRead,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -36,14 +37,18 @@ func dataSourceOpsGenieService() *schema.Resource {
 	}
 }
 
-func dataSourceOpsGenieServiceRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceOpsGenieServiceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// OpsGenie async call to create service might take a bit of time to take affect.
 	// This sleep will make sure we are not hitting 404 error if hit get/list service API before creation could happen.
-	time.Sleep(5 * time.Second)
+	select {
+	case <-ctx.Done():
+		return diag.FromErr(ctx.Err())
+	case <-time.After(5 * time.Second):
+	}
 
 	client, err := service.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 
@@ -53,12 +58,12 @@ func dataSourceOpsGenieServiceRead(d *schema.ResourceData, meta interface{}) err
 	offset := 0
 
 	for {
-		res, err := client.List(context.Background(), &service.ListRequest{
+		res, err := client.List(ctx, &service.ListRequest{
 			Limit:  100,
 			Offset: offset,
 		})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		log.Printf("[DEBUG] Searching for service name: '%s' in your account", name)
@@ -84,7 +89,7 @@ func dataSourceOpsGenieServiceRead(d *schema.ResourceData, meta interface{}) err
 		offset, err = strconv.Atoi(offsetString)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	return nil
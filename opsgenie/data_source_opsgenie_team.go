@@ -2,16 +2,19 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
 )
 
 func dataSourceOpsGenieTeam() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceOpsGenieTeamRead,
+		ReadContext: dataSourceOpsGenieTeamRead,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -40,14 +43,152 @@ func dataSourceOpsGenieTeam() *schema.Resource {
 					},
 				},
 			},
+			"expand_routing_rules": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"expand_owned_resources": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"routing_rule": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_default": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"order": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"timezone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"notify": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"criteria": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"conditions": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"field": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"key": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"not": {
+													Type:     schema.TypeBool,
+													Computed: true,
+												},
+												"operation": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"expected_value": {
+													Type:     schema.TypeString,
+													Computed: true,
+												},
+												"order": {
+													Type:     schema.TypeInt,
+													Computed: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"schedule": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"escalation": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func dataSourceOpsGenieTeamRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceOpsGenieTeamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	teamName := d.Get("name").(string)
 
@@ -56,14 +197,83 @@ func dataSourceOpsGenieTeamRead(d *schema.ResourceData, meta interface{}) error
 		IdentifierValue: teamName,
 	}
 
-	getResponse, err := client.Get(context.Background(), getRequest)
+	getResponse, err := client.Get(ctx, getRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(getResponse.Id)
 
 	d.Set("description", getResponse.Description)
 	d.Set("member", flattenOpsGenieTeamMembers(getResponse.Members))
 
+	if d.Get("expand_routing_rules").(bool) {
+		rules, err := client.ListRoutingRules(ctx, &team.ListRoutingRulesRequest{
+			TeamIdentifierType:  team.Id,
+			TeamIdentifierValue: getResponse.Id,
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("routing_rule", flattenOpsGenieTeamRoutingRules(rules.RoutingRules))
+	}
+
+	if d.Get("expand_owned_resources").(bool) {
+		config := meta.(*OpsgenieClient).client.Config
+
+		schedules, err := findOpsGenieTeamSchedules(ctx, getResponse.Name, config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("schedule", flattenOpsGenieTeamSchedules(schedules))
+
+		escalations, err := findOpsGenieTeamEscalations(ctx, getResponse.Name, config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("escalation", flattenOpsGenieTeamEscalations(escalations))
+	}
+
 	return nil
 }
+
+func flattenOpsGenieTeamRoutingRules(input []team.RoutingRuleMeta) []map[string]interface{} {
+	rules := make([]map[string]interface{}, 0, len(input))
+	for i, rule := range input {
+		out := make(map[string]interface{})
+		out["id"] = rule.Id
+		out["name"] = rule.Name
+		out["is_default"] = rule.IsDefault
+		out["order"] = i
+		out["timezone"] = rule.Timezone
+		out["notify"] = flattenOpsgenieNotify(rule.Notify)
+		out["criteria"] = flattenOpsgenieCriteria(rule.Criteria)
+		rules = append(rules, out)
+	}
+
+	return rules
+}
+
+func flattenOpsGenieTeamSchedules(input []schedule.Schedule) []map[string]interface{} {
+	schedules := make([]map[string]interface{}, 0, len(input))
+	for _, sched := range input {
+		out := make(map[string]interface{})
+		out["id"] = sched.Id
+		out["name"] = sched.Name
+		out["enabled"] = sched.Enabled
+		schedules = append(schedules, out)
+	}
+
+	return schedules
+}
+
+func flattenOpsGenieTeamEscalations(input []escalation.Escalation) []map[string]interface{} {
+	escalations := make([]map[string]interface{}, 0, len(input))
+	for _, escal := range input {
+		out := make(map[string]interface{})
+		out["id"] = escal.Id
+		out["name"] = escal.Name
+		escalations = append(escalations, out)
+	}
+
+	return escalations
+}
@@ -0,0 +1,113 @@
+package opsgenie
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+)
+
+func dataSourceOpsGenieTeamLogs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceOpsGenieTeamLogsRead,
+		Schema: map[string]*schema.Schema{
+			"team_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"team_id", "team_name"},
+			},
+			"team_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOpsGenieTeamName,
+				ExactlyOneOf: []string{"team_id", "team_name"},
+			},
+			"offset": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      20,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+			"order": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "desc",
+				ValidateFunc: validation.StringInSlice([]string{"asc", "desc"}, false),
+			},
+			"next_offset": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"log": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceOpsGenieTeamLogsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	listRequest := &team.ListTeamLogsRequest{
+		IdentifierType:  team.Id,
+		IdentifierValue: d.Get("team_id").(string),
+		Offset:          d.Get("offset").(int),
+		Limit:           d.Get("limit").(int),
+		Order:           d.Get("order").(string),
+	}
+	if teamName := d.Get("team_name").(string); teamName != "" {
+		listRequest.IdentifierType = team.Name
+		listRequest.IdentifierValue = teamName
+	}
+
+	result, err := client.ListTeamLogs(ctx, listRequest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(listRequest.IdentifierValue)
+	d.Set("next_offset", result.Offset)
+	d.Set("log", flattenOpsGenieTeamLogs(result.Logs))
+
+	return nil
+}
+
+func flattenOpsGenieTeamLogs(input []team.LogEntry) []map[string]interface{} {
+	logs := make([]map[string]interface{}, 0, len(input))
+	for _, entry := range input {
+		out := make(map[string]interface{})
+		out["owner"] = entry.Owner
+		out["created_date"] = entry.CreatedDate
+		out["message"] = entry.Log
+		logs = append(logs, out)
+	}
+
+	return logs
+}
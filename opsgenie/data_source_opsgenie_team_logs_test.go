@@ -0,0 +1,62 @@
+package opsgenie
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceOpsGenieTeamLogs_Basic(t *testing.T) {
+	randomTeamName := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOpsGenieTeamLogsConfig(randomTeamName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceOpsGenieTeamLogs("opsgenie_team.test", "data.opsgenie_team_logs.test"),
+					resource.TestCheckResourceAttr("data.opsgenie_team_logs.test", "order", "asc"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceOpsGenieTeamLogs(src, n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		srcR := s.RootModule().Resources[src]
+		srcA := srcR.Primary.Attributes
+
+		r := s.RootModule().Resources[n]
+		a := r.Primary.Attributes
+
+		if a["id"] != srcA["id"] {
+			return fmt.Errorf("Expected the team logs id to be: %s, but got: %s", srcA["id"], a["id"])
+		}
+
+		if a["log.#"] == "" || a["log.#"] == "0" {
+			return fmt.Errorf("Expected to get at least one log entry for team %s", srcA["name"])
+		}
+
+		return nil
+	}
+}
+
+func testAccDataSourceOpsGenieTeamLogsConfig(randomTeamName string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_team" "test" {
+  name        = "genieteam-%s"
+  description = "This team deals with all the things"
+}
+data "opsgenie_team_logs" "test" {
+  team_id    = "${opsgenie_team.test.id}"
+  order      = "asc"
+  depends_on = [opsgenie_team.test]
+}
+`, randomTeamName)
+}
@@ -26,6 +26,27 @@ func TestAccDataSourceOpsGenieTeam_Basic(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceOpsGenieTeam_Expanded(t *testing.T) {
+	randomName := acctest.RandString(6)
+	randomTeamName := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOpsGenieTeamConfigExpanded(randomName, randomTeamName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceOpsGenieTeam("opsgenie_team.test", "data.opsgenie_team.existingteam"),
+					resource.TestCheckResourceAttr("data.opsgenie_team.existingteam", "routing_rule.#", "1"),
+					resource.TestCheckResourceAttr("data.opsgenie_team.existingteam", "routing_rule.0.is_default", "true"),
+					resource.TestCheckResourceAttr("data.opsgenie_team.existingteam", "schedule.#", "1"),
+					resource.TestCheckResourceAttr("data.opsgenie_team.existingteam", "escalation.#", "1"),
+				),
+			},
+		},
+	})
+}
+
 func testAccDataSourceOpsGenieTeam(src, n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 
@@ -82,3 +103,28 @@ data "opsgenie_team" "existingteam" {
 }
 `, randomName, randomName, randomTeamName)
 }
+
+func testAccDataSourceOpsGenieTeamConfigExpanded(randomName, randomTeamName string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_user" "test" {
+  username  = "genietest-%s@opsgenie.com"
+  full_name = "Acceptance Test User"
+  role      = "User"
+}
+resource "opsgenie_team" "test" {
+  name        = "genieteam-%s"
+  description = "This team deals with all the things"
+
+  member {
+    id = "${opsgenie_user.test.id}"
+    role     = "admin"
+  }
+}
+data "opsgenie_team" "existingteam" {
+  name                   = "${opsgenie_team.test.name}"
+  expand_routing_rules   = true
+  expand_owned_resources = true
+  depends_on             = [opsgenie_team.test]
+}
+`, randomName, randomTeamName)
+}
@@ -0,0 +1,560 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/integration"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/policy"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/service"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
+)
+
+// GenerateOptions configures GenerateConfig.
+type GenerateOptions struct {
+	ApiKey string
+	ApiUrl string
+	Region string
+
+	// ResourceTypes limits the generated resources to the given types, e.g.
+	// opsgenie_team. All supported types are generated if it is empty.
+	ResourceTypes []string
+}
+
+// generatedResource is an existing object of the account, identified by the
+// ID that the importer of its resource type accepts.
+type generatedResource struct {
+	resourceType string
+	importId     string
+	name         string
+
+	label string
+	data  *schema.ResourceData
+}
+
+type resourceLister func(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error)
+
+// generatedResourceTypes are the resource types GenerateConfig supports, in
+// the order they are written.
+var generatedResourceTypes = []struct {
+	resourceType string
+	list         resourceLister
+}{
+	{"opsgenie_user", listUsersForGenerate},
+	{"opsgenie_team", listTeamsForGenerate},
+	{"opsgenie_schedule", listSchedulesForGenerate},
+	{"opsgenie_schedule_rotation", listScheduleRotationsForGenerate},
+	{"opsgenie_escalation", listEscalationsForGenerate},
+	{"opsgenie_api_integration", listApiIntegrationsForGenerate},
+	{"opsgenie_email_integration", listEmailIntegrationsForGenerate},
+	{"opsgenie_alert_policy", listAlertPoliciesForGenerate},
+	{"opsgenie_notification_policy", listNotificationPoliciesForGenerate},
+	{"opsgenie_service", listServicesForGenerate},
+}
+
+// GenerateConfig writes the configuration of the resources in an OpsGenie
+// account to w, together with import blocks for all of them. The resources
+// are read with the importers and read functions of the provider, so that
+// planning the generated configuration after the import is a no-op.
+func GenerateConfig(ctx context.Context, opts GenerateOptions, w io.Writer) error {
+	apiUrl, err := opsGenieApiUrl(opts.ApiUrl, opts.Region)
+	if err != nil {
+		return err
+	}
+
+	config := Config{
+		ApiKey:           opts.ApiKey,
+		ApiUrl:           apiUrl,
+		RetryCount:       10,
+		RetryWaitMin:     time.Second,
+		RetryWaitMax:     30 * time.Second,
+		RetryOnRateLimit: true,
+	}
+	meta, err := config.Client()
+	if err != nil {
+		return err
+	}
+	if err := meta.validateCredentials(ctx); err != nil {
+		return err
+	}
+
+	provider := Provider()
+	var resources []*generatedResource
+	for _, t := range generatedResourceTypes {
+		if len(opts.ResourceTypes) > 0 && !stringInSlice(t.resourceType, opts.ResourceTypes) {
+			continue
+		}
+
+		log.Printf("[INFO] Listing %s resources", t.resourceType)
+		listed, err := t.list(ctx, meta)
+		if err != nil {
+			return fmt.Errorf("could not list %s resources: %s", t.resourceType, err)
+		}
+
+		res := provider.ResourcesMap[t.resourceType]
+		for _, r := range listed {
+			r.resourceType = t.resourceType
+			r.data, err = readForGenerate(ctx, res, r.importId, meta)
+			if err != nil {
+				return fmt.Errorf("could not read %s %q: %s", t.resourceType, r.importId, err)
+			}
+			if r.data == nil {
+				log.Printf("[WARN] %s %q no longer exists, skipping", t.resourceType, r.importId)
+				continue
+			}
+			resources = append(resources, r)
+		}
+	}
+
+	labels := make(map[string]bool)
+	references := make(map[string]string)
+	for _, r := range resources {
+		r.label = uniqueLabel(labels, r.resourceType, r.name)
+		references[r.data.Id()] = fmt.Sprintf("%s.%s.id", r.resourceType, r.label)
+	}
+
+	fmt.Fprintf(w, "# Generated from the OpsGenie account at %s\n", apiUrl)
+	for _, r := range resources {
+		res := provider.ResourcesMap[r.resourceType]
+		fmt.Fprintf(w, "\nimport {\n  to = %s.%s\n  id = %s\n}\n\n", r.resourceType, r.label, hclString(r.importId))
+		fmt.Fprintf(w, "resource %q %q {\n", r.resourceType, r.label)
+		writeHclBody(w, "  ", res.Schema, resourceDataValues(res.Schema, r.data), references, r.data.Id())
+		fmt.Fprintf(w, "}\n")
+	}
+
+	return nil
+}
+
+// readForGenerate reads a resource the way terraform import does, by
+// running the importer of res with importId and reading the result. It
+// returns nil if the resource does not exist.
+func readForGenerate(ctx context.Context, res *schema.Resource, importId string, meta *OpsgenieClient) (*schema.ResourceData, error) {
+	d := res.Data(nil)
+	d.SetId(importId)
+
+	if res.Importer != nil && res.Importer.StateContext != nil {
+		imported, err := res.Importer.StateContext(ctx, d, meta)
+		if err != nil {
+			return nil, err
+		}
+		d = imported[0]
+	}
+
+	if diags := res.ReadContext(ctx, d, meta); diags.HasError() {
+		for _, diag := range diags {
+			return nil, fmt.Errorf("%s", diag.Summary)
+		}
+	}
+	if d.Id() == "" {
+		return nil, nil
+	}
+	return d, nil
+}
+
+func listUsersForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	client, err := user.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for offset := 0; ; offset += 100 {
+		result, err := client.List(ctx, &user.ListRequest{Limit: 100, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range result.Users {
+			resources = append(resources, &generatedResource{importId: u.Id, name: strings.Split(u.Username, "@")[0]})
+		}
+		if len(result.Users) < 100 {
+			return resources, nil
+		}
+	}
+}
+
+func listTeamsForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	teams, err := listTeamsForGenerateResults(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, t := range teams {
+		resources = append(resources, &generatedResource{importId: t.Id, name: t.Name})
+	}
+	return resources, nil
+}
+
+func listTeamsForGenerateResults(ctx context.Context, meta *OpsgenieClient) ([]team.ListedTeams, error) {
+	client, err := team.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.List(ctx, &team.ListTeamRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Teams, nil
+}
+
+func listSchedulesForGenerateResults(ctx context.Context, meta *OpsgenieClient) ([]schedule.Schedule, error) {
+	client, err := schedule.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	expand := false
+	result, err := client.List(ctx, &schedule.ListRequest{Expand: &expand})
+	if err != nil {
+		return nil, err
+	}
+	return result.Schedule, nil
+}
+
+func listSchedulesForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	schedules, err := listSchedulesForGenerateResults(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, s := range schedules {
+		resources = append(resources, &generatedResource{importId: s.Id, name: s.Name})
+	}
+	return resources, nil
+}
+
+func listScheduleRotationsForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	schedules, err := listSchedulesForGenerateResults(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+	client, err := schedule.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, s := range schedules {
+		result, err := client.ListRotations(ctx, &schedule.ListRotationsRequest{
+			ScheduleIdentifierType:  schedule.Id,
+			ScheduleIdentifierValue: s.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range result.Rotations {
+			resources = append(resources, &generatedResource{importId: s.Id + "/" + r.Id, name: s.Name + "_" + r.Name})
+		}
+	}
+	return resources, nil
+}
+
+func listEscalationsForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	client, err := escalation.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, e := range result.Escalations {
+		resources = append(resources, &generatedResource{importId: e.Id, name: e.Name})
+	}
+	return resources, nil
+}
+
+func listIntegrationsForGenerate(ctx context.Context, meta *OpsgenieClient, email bool) ([]*generatedResource, error) {
+	client, err := integration.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, i := range result.Integrations {
+		if (i.Type == "Email") == email {
+			resources = append(resources, &generatedResource{importId: i.Id, name: i.Name})
+		}
+	}
+	return resources, nil
+}
+
+// listApiIntegrationsForGenerate lists all integrations but email
+// integrations. opsgenie_api_integration only manages the settings that all
+// integration types share.
+func listApiIntegrationsForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	return listIntegrationsForGenerate(ctx, meta, false)
+}
+
+func listEmailIntegrationsForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	return listIntegrationsForGenerate(ctx, meta, true)
+}
+
+func listAlertPoliciesForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	client, err := policy.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := listTeamsForGenerateResults(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	// global policies are listed without a team
+	result, err := client.ListAlertPolicies(ctx, &policy.ListAlertPoliciesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var resources []*generatedResource
+	for _, p := range result.Policies {
+		resources = append(resources, &generatedResource{importId: p.Id, name: p.Name})
+	}
+
+	for _, t := range teams {
+		result, err := client.ListAlertPolicies(ctx, &policy.ListAlertPoliciesRequest{TeamId: t.Id})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.Policies {
+			resources = append(resources, &generatedResource{importId: t.Id + "/" + p.Id, name: t.Name + "_" + p.Name})
+		}
+	}
+	return resources, nil
+}
+
+func listNotificationPoliciesForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	client, err := policy.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := listTeamsForGenerateResults(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for _, t := range teams {
+		result, err := client.ListNotificationPolicies(ctx, &policy.ListNotificationPoliciesRequest{TeamId: t.Id})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.Policies {
+			resources = append(resources, &generatedResource{importId: t.Id + "/" + p.Id, name: t.Name + "_" + p.Name})
+		}
+	}
+	return resources, nil
+}
+
+func listServicesForGenerate(ctx context.Context, meta *OpsgenieClient) ([]*generatedResource, error) {
+	client, err := service.NewClient(meta.client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*generatedResource
+	for offset := 0; ; offset += 100 {
+		result, err := client.List(ctx, &service.ListRequest{Limit: 100, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range result.Services {
+			resources = append(resources, &generatedResource{importId: s.Id, name: s.Name})
+		}
+		if len(result.Services) < 100 {
+			return resources, nil
+		}
+	}
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// uniqueLabel turns name into a resource label that is unique for its type.
+func uniqueLabel(labels map[string]bool, resourceType, name string) string {
+	label := strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "r_" + label
+	}
+
+	unique := label
+	for i := 2; labels[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	labels[resourceType+"."+unique] = true
+	return unique
+}
+
+func resourceDataValues(s map[string]*schema.Schema, d *schema.ResourceData) map[string]interface{} {
+	values := make(map[string]interface{}, len(s))
+	for k := range s {
+		values[k] = d.Get(k)
+	}
+	return values
+}
+
+// writeHclBody writes the arguments of values that differ from their
+// defaults, with attributes before nested blocks. String values of
+// attributes ending in "id" that are the ID of another generated resource are
+// written as a reference to it.
+func writeHclBody(w io.Writer, indent string, s map[string]*schema.Schema, values map[string]interface{}, references map[string]string, selfId string) {
+	var attributes, blocks []string
+	for k, sch := range s {
+		if sch.Computed && !sch.Optional && !sch.Required {
+			continue
+		}
+		if !sch.Required && isDefaultValue(sch, values[k]) {
+			continue
+		}
+		if _, ok := sch.Elem.(*schema.Resource); ok && (sch.Type == schema.TypeList || sch.Type == schema.TypeSet) {
+			blocks = append(blocks, k)
+		} else {
+			attributes = append(attributes, k)
+		}
+	}
+	sort.Strings(attributes)
+	sort.Strings(blocks)
+
+	// align the equals signs like terraform fmt does
+	width := 0
+	for _, k := range attributes {
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+
+	for _, k := range attributes {
+		value := hclValue(s[k], values[k])
+		if id, ok := values[k].(string); ok && strings.HasSuffix(k, "id") && id != selfId && references[id] != "" {
+			value = references[id]
+		}
+		fmt.Fprintf(w, "%s%-*s = %s\n", indent, width, k, value)
+	}
+
+	for _, k := range blocks {
+		elem := s[k].Elem.(*schema.Resource)
+		for _, v := range listValue(values[k]) {
+			blockValues, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(w, "\n%s%s {\n", indent, k)
+			writeHclBody(w, indent+"  ", elem.Schema, blockValues, references, selfId)
+			fmt.Fprintf(w, "%s}\n", indent)
+		}
+	}
+}
+
+func isDefaultValue(sch *schema.Schema, value interface{}) bool {
+	if sch.Default != nil {
+		return fmt.Sprint(sch.Default) == fmt.Sprint(value)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return len(listValue(value)) == 0
+	}
+}
+
+func listValue(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case *schema.Set:
+		return v.List()
+	}
+	return nil
+}
+
+func hclValue(sch *schema.Schema, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return hclString(v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		entries := make([]string, 0, len(v))
+		for _, k := range keys {
+			entries = append(entries, fmt.Sprintf("%s = %s", hclString(k), hclValue(nil, v[k])))
+		}
+		return "{ " + strings.Join(entries, ", ") + " }"
+	case []interface{}, *schema.Set:
+		list := listValue(v)
+		if sch != nil && sch.Type == schema.TypeSet {
+			sort.Slice(list, func(i, j int) bool { return fmt.Sprint(list[i]) < fmt.Sprint(list[j]) })
+		}
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, hclValue(nil, item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// hclString quotes s as an HCL string, escaping template sequences.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && i+1 < len(s) && s[i+1] == '{':
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
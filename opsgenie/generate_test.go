@@ -0,0 +1,103 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+
+	config := &client.Config{ApiKey: "key", OpsGenieAPIURL: client.ApiUrl(server.ApiUrl()), RetryCount: 1}
+	teamClient, err := team.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := teamClient.Create(ctx, &team.CreateTeamRequest{Name: "platform", Description: "Platform ${team}"}); err != nil {
+		t.Fatal(err)
+	}
+	userClient, err := user.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := userClient.Create(ctx, &user.CreateRequest{Username: "jane@example.com", FullName: "Jane", Role: &user.UserRoleRequest{RoleName: "User"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := GenerateOptions{
+		ApiKey:        "key",
+		ApiUrl:        server.ApiUrl(),
+		ResourceTypes: []string{"opsgenie_user", "opsgenie_team", "opsgenie_schedule", "opsgenie_escalation"},
+	}
+	if err := GenerateConfig(ctx, opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	generated := out.String()
+
+	for _, expected := range []string{
+		"import {\n  to = opsgenie_team.platform\n",
+		`resource "opsgenie_team" "platform" {`,
+		`description = "Platform $${team}"`,
+		`resource "opsgenie_user" "jane" {`,
+		`username  = "jane@example.com"`,
+		`resource "opsgenie_schedule" "platform_schedule" {`,
+		"owner_team_id = opsgenie_team.platform.id",
+		"notify_type = \"default\"",
+		`resource "opsgenie_escalation" "platform_escalation" {`,
+		"id   = opsgenie_schedule.platform_schedule.id",
+	} {
+		if !strings.Contains(generated, expected) {
+			t.Errorf("Expected the generated configuration to contain %q, got:\n%s", expected, generated)
+		}
+	}
+	if strings.Contains(generated, "opsgenie_service") {
+		t.Errorf("Expected only the requested resource types, got:\n%s", generated)
+	}
+}
+
+func TestUniqueLabel(t *testing.T) {
+	labels := make(map[string]bool)
+	cases := []struct {
+		resourceType string
+		name         string
+		expected     string
+	}{
+		{"opsgenie_team", "Platform Team", "platform_team"},
+		{"opsgenie_team", "platform-team", "platform_team_2"},
+		{"opsgenie_schedule", "platform team", "platform_team"},
+		{"opsgenie_team", "24/7 support", "r_24_7_support"},
+		{"opsgenie_team", "", "r_"},
+	}
+
+	for _, c := range cases {
+		if label := uniqueLabel(labels, c.resourceType, c.name); label != c.expected {
+			t.Errorf("Expected label %q for %q, got %q", c.expected, c.name, label)
+		}
+	}
+}
+
+func TestHclString(t *testing.T) {
+	cases := map[string]string{
+		"plain":          `"plain"`,
+		`say "hi"`:       `"say \"hi\""`,
+		"line\nbreak":    `"line\nbreak"`,
+		"${var} %{if}":   `"$${var} %%{if}"`,
+		"cost $5 and 5%": `"cost $5 and 5%"`,
+	}
+
+	for s, expected := range cases {
+		if quoted := hclString(s); quoted != expected {
+			t.Errorf("Expected %s for %q, got %s", expected, s, quoted)
+		}
+	}
+}
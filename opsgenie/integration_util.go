@@ -20,8 +20,10 @@ func expandOpsgenieIntegrationResponders(d *schema.ResourceData) []integration.R
 		config := v.(map[string]interface{})
 		responderID := config["id"].(string)
 		responder := integration.Responder{
-			Type: integration.ResponderType(config["type"].(string)),
-			Id:   responderID,
+			Type:     integration.ResponderType(config["type"].(string)),
+			Id:       responderID,
+			Name:     config["name"].(string),
+			Username: config["username"].(string),
 		}
 
 		responders = append(responders, responder)
@@ -34,9 +36,13 @@ func flattenIntegrationResponders(r []interface{}) []map[string]interface{} {
 	responders := []map[string]interface{}{}
 	for _, i := range r {
 		c := i.(map[string]interface{})
+		name, _ := c["name"].(string)
+		username, _ := c["username"].(string)
 		responders = append(responders, map[string]interface{}{
-			"type": c["type"],
-			"id":   c["id"],
+			"type":     c["type"],
+			"id":       c["id"],
+			"name":     name,
+			"username": username,
 		})
 	}
 	return responders
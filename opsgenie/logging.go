@@ -0,0 +1,139 @@
+package opsgenie
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	redactedJsonFields = regexp.MustCompile(`(?i)("(?:apiKey|api_key|password|token|secret)"\s*:\s*)"[^"]*"`)
+	redactedGoFields   = regexp.MustCompile(`(?i)((?:ApiKey|Password|Token|Secret):)[^\s}]+`)
+	redactedGenieKeys  = regexp.MustCompile(`(?i)(GenieKey\s+)[^\s"]+`)
+
+	registerTraceSubscriber sync.Once
+)
+
+// sanitize redacts API keys and other secrets from SDK log messages and
+// dumped HTTP requests and responses.
+func sanitize(s string) string {
+	s = redactedJsonFields.ReplaceAllString(s, `$1"<redacted>"`)
+	s = redactedGoFields.ReplaceAllString(s, `$1<redacted>`)
+	return redactedGenieKeys.ReplaceAllString(s, `$1<redacted>`)
+}
+
+// terraformLogHook forwards SDK log entries to the standard logger, which
+// Terraform filters according to TF_LOG.
+type terraformLogHook struct{}
+
+func (h *terraformLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *terraformLogHook) Fire(entry *logrus.Entry) error {
+	log.Printf("[%s] OpsGenie SDK: %s", terraformLogLevel(entry.Level), sanitize(entry.Message))
+	return nil
+}
+
+func terraformLogLevel(level logrus.Level) string {
+	switch level {
+	case logrus.TraceLevel:
+		return "TRACE"
+	case logrus.DebugLevel:
+		return "DEBUG"
+	case logrus.InfoLevel:
+		return "INFO"
+	case logrus.WarnLevel:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+func logrusLevel(tfLogLevel string) logrus.Level {
+	switch tfLogLevel {
+	case "TRACE":
+		return logrus.TraceLevel
+	case "DEBUG":
+		return logrus.DebugLevel
+	case "INFO":
+		return logrus.InfoLevel
+	case "ERROR":
+		return logrus.ErrorLevel
+	default:
+		return logrus.WarnLevel
+	}
+}
+
+// newSdkLogger returns a logger for the SDK that discards its own output and
+// only writes through terraformLogHook, at the level set by TF_LOG.
+func newSdkLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	logger.SetLevel(logrusLevel(logging.LogLevel()))
+	logger.AddHook(&terraformLogHook{})
+	return logger
+}
+
+// traceTransport dumps sanitized HTTP requests and responses at TRACE level.
+// Responses are tagged with the X-Request-Id of OpsGenie, which is also logged
+// together with the SDK transaction id by logApiMetric.
+type traceTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if reqData, err := httputil.DumpRequestOut(req, true); err == nil {
+		log.Printf("[TRACE] OpsGenie API Request %s %s:\n%s", req.Method, req.URL.Path, sanitize(string(reqData)))
+	} else {
+		log.Printf("[ERROR] OpsGenie API Request error: %s", err)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if respData, err := httputil.DumpResponse(resp, true); err == nil {
+		log.Printf("[TRACE] OpsGenie API Response %s %s (X-Request-Id: %s):\n%s", req.Method, req.URL.Path, resp.Header.Get("X-Request-Id"), sanitize(string(respData)))
+	} else {
+		log.Printf("[ERROR] OpsGenie API Response error: %s", err)
+	}
+
+	return resp, nil
+}
+
+// enableRequestTracing wraps the transport of httpClient with traceTransport
+// and logs the SDK transaction id of every API call, if TF_LOG is TRACE.
+func enableRequestTracing(httpClient *http.Client) {
+	if logging.LogLevel() != "TRACE" {
+		return
+	}
+
+	httpClient.Transport = &traceTransport{transport: httpClient.Transport}
+
+	// subscribers are registered globally in the SDK, so only register once
+	// even if the provider is configured multiple times
+	registerTraceSubscriber.Do(func() {
+		subscriber := &client.MetricSubscriber{Process: logApiMetric}
+		subscriber.Register(client.API)
+	})
+}
+
+func logApiMetric(metric client.Metric) interface{} {
+	apiMetric, ok := metric.(*client.ApiMetric)
+	if !ok {
+		return nil
+	}
+
+	log.Printf("[TRACE] OpsGenie SDK transaction %s: %s returned %d in %dms (X-Request-Id: %s)",
+		apiMetric.TransactionId, apiMetric.ResourcePath, apiMetric.HttpResponse.StatusCode, apiMetric.Duration, apiMetric.ResultMetadata.RequestId)
+	return nil
+}
@@ -0,0 +1,43 @@
+package opsgenie
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := map[string]string{
+		"Authorization: GenieKey 1234-abcd\r\n":                            "Authorization: GenieKey <redacted>\r\n",
+		`{"name":"api","apiKey":"1234-abcd","enabled":true}`:               `{"name":"api","apiKey":"<redacted>","enabled":true}`,
+		`{"password": "hunter2"}`:                                          `{"password": "<redacted>"}`,
+		"Request processed. The result: &{Name:api ApiKey:1234-abcd Id:1}": "Request processed. The result: &{Name:api ApiKey:<redacted> Id:1}",
+		"nothing to hide": "nothing to hide",
+	}
+
+	for input, expected := range cases {
+		if actual := sanitize(input); actual != expected {
+			t.Fatalf("Expected %q to be sanitized to %q, got %q", input, expected, actual)
+		}
+	}
+}
+
+func TestLogrusLevel(t *testing.T) {
+	cases := map[string]logrus.Level{
+		"TRACE": logrus.TraceLevel,
+		"DEBUG": logrus.DebugLevel,
+		"INFO":  logrus.InfoLevel,
+		"WARN":  logrus.WarnLevel,
+		"ERROR": logrus.ErrorLevel,
+		"":      logrus.WarnLevel,
+	}
+
+	for tfLogLevel, expected := range cases {
+		if actual := logrusLevel(tfLogLevel); actual != expected {
+			t.Fatalf("Expected TF_LOG=%q to map to %s, got %s", tfLogLevel, expected, actual)
+		}
+		if tfLogLevel != "" && terraformLogLevel(expected) != tfLogLevel {
+			t.Fatalf("Expected %s to map back to %s, got %s", expected, tfLogLevel, terraformLogLevel(expected))
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
+)
+
+var (
+	resourcePathIds = regexp.MustCompile(`/[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}(?:-\d+)?`)
+
+	apiMetrics = &metricsCollector{paths: make(map[string]*pathMetrics), operations: make(map[string]bool)}
+
+	registerMetricsSubscriber sync.Once
+)
+
+// metricsCollector aggregates the API calls made by all clients of the
+// provider process. Calls and their latency are recorded from the HTTP
+// metrics of the SDK, while every single attempt is recorded by
+// metricsTransport, as the SDK does not report retries that succeeded.
+type metricsCollector struct {
+	mu         sync.Mutex
+	enabled    bool
+	outputFile string
+	started    time.Time
+	paths      map[string]*pathMetrics
+	operations map[string]bool
+	// written is the number of calls and attempts in the last summary
+	// written to outputFile
+	written int
+}
+
+type pathMetrics struct {
+	calls       int
+	attempts    int
+	rateLimited int
+	durations   []int64
+}
+
+type metricsSummary struct {
+	Pid         int                   `json:"pid"`
+	Operations  []string              `json:"operations"`
+	Started     time.Time             `json:"started"`
+	Finished    time.Time             `json:"finished"`
+	Calls       int                   `json:"calls"`
+	Retries     int                   `json:"retries"`
+	RateLimited int                   `json:"rate_limited"`
+	P95Latency  int64                 `json:"p95_latency_ms"`
+	Paths       []resourcePathSummary `json:"paths"`
+}
+
+type resourcePathSummary struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Calls       int    `json:"calls"`
+	Retries     int    `json:"retries"`
+	RateLimited int    `json:"rate_limited"`
+	P95Latency  int64  `json:"p95_latency_ms"`
+}
+
+// enableMetrics starts collecting API metrics and wraps the transport of
+// httpClient with metricsTransport.
+func enableMetrics(httpClient *http.Client, outputFile string) {
+	apiMetrics.mu.Lock()
+	if !apiMetrics.enabled {
+		apiMetrics.enabled = true
+		apiMetrics.started = time.Now()
+	}
+	apiMetrics.outputFile = outputFile
+	apiMetrics.mu.Unlock()
+
+	httpClient.Transport = &metricsTransport{transport: httpClient.Transport}
+
+	// subscribers are registered globally in the SDK, so only register once
+	// even if the provider is configured multiple times
+	registerMetricsSubscriber.Do(func() {
+		subscriber := &client.MetricSubscriber{Process: apiMetrics.recordHttpMetric}
+		subscriber.Register(client.HTTP)
+	})
+}
+
+// WriteMetricsSummary logs the summary of the API calls made by the provider
+// process, and writes it to the output file of the process. It does nothing
+// unless the metrics summary was enabled in the provider configuration.
+//
+// It is called once Terraform shut the provider down, when the log output
+// may no longer be read. The output file is kept up to date by
+// ProviderServer in the meantime.
+func WriteMetricsSummary() {
+	apiMetrics.mu.Lock()
+	defer apiMetrics.mu.Unlock()
+
+	if !apiMetrics.enabled || len(apiMetrics.paths) == 0 {
+		return
+	}
+
+	summary := apiMetrics.summary(time.Now())
+	log.Printf("[INFO] OpsGenie API metrics summary:\n%s", summary)
+	apiMetrics.write(summary)
+}
+
+// ProviderServer returns the provider served over gRPC, which writes the
+// metrics summary of the process after every operation that calls the API.
+func ProviderServer() tfprotov5.ProviderServer {
+	return &metricsProviderServer{ProviderServer: schema.NewGRPCProviderServer(Provider())}
+}
+
+type metricsProviderServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s *metricsProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	defer apiMetrics.flush("ConfigureProvider")
+	return s.ProviderServer.ConfigureProvider(ctx, req)
+}
+
+func (s *metricsProviderServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	defer apiMetrics.flush("ReadResource")
+	return s.ProviderServer.ReadResource(ctx, req)
+}
+
+func (s *metricsProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	defer apiMetrics.flush("PlanResourceChange")
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
+
+func (s *metricsProviderServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	defer apiMetrics.flush("ApplyResourceChange")
+	return s.ProviderServer.ApplyResourceChange(ctx, req)
+}
+
+func (s *metricsProviderServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	defer apiMetrics.flush("ImportResourceState")
+	return s.ProviderServer.ImportResourceState(ctx, req)
+}
+
+func (s *metricsProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	defer apiMetrics.flush("ReadDataSource")
+	return s.ProviderServer.ReadDataSource(ctx, req)
+}
+
+// flush records that the operation ran, and writes the summary to the output
+// file if API calls were made since it was last written.
+func (m *metricsCollector) flush(operation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.enabled {
+		return
+	}
+	m.operations[operation] = true
+
+	total := 0
+	for _, p := range m.paths {
+		total += p.calls + p.attempts
+	}
+	if m.outputFile == "" || total == m.written {
+		return
+	}
+	m.write(m.summary(time.Now()))
+	m.written = total
+}
+
+// write writes the summary to the output file of the process. Terraform
+// starts a provider process for each command and phase, e.g. one for the plan
+// and one for the apply of terraform apply, so every process writes its own
+// file, see metricsSummaryPath.
+func (m *metricsCollector) write(summary *metricsSummary) {
+	if m.outputFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		log.Printf("[ERROR] Could not encode the OpsGenie API metrics summary: %s", err)
+		return
+	}
+	path := metricsSummaryPath(m.outputFile, summary.Pid)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		log.Printf("[ERROR] Could not write the OpsGenie API metrics summary to %s: %s", path, err)
+	}
+}
+
+// metricsSummaryPath returns the path of the summary of the process with the
+// given pid, which is inserted before the extension of outputFile, e.g.
+// opsgenie-metrics-1234.json.
+func metricsSummaryPath(outputFile string, pid int) string {
+	ext := filepath.Ext(outputFile)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(outputFile, ext), pid, ext)
+}
+
+func (m *metricsCollector) recordHttpMetric(metric client.Metric) interface{} {
+	httpMetric, ok := metric.(*client.HttpMetric)
+	if !ok || httpMetric.HttpRequest.Request == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.path(httpMetric.HttpRequest.Method, httpMetric.ResourcePath)
+	p.calls++
+	p.durations = append(p.durations, httpMetric.Duration)
+	return nil
+}
+
+func (m *metricsCollector) recordAttempt(method, resourcePath string, statusCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.path(method, resourcePath)
+	p.attempts++
+	if statusCode == http.StatusTooManyRequests {
+		p.rateLimited++
+	}
+}
+
+func (m *metricsCollector) path(method, resourcePath string) *pathMetrics {
+	key := method + " " + resourcePathIds.ReplaceAllString(resourcePath, "/{id}")
+	p, ok := m.paths[key]
+	if !ok {
+		p = &pathMetrics{}
+		m.paths[key] = p
+	}
+	return p
+}
+
+func (m *metricsCollector) summary(finished time.Time) *metricsSummary {
+	summary := &metricsSummary{
+		Pid:        os.Getpid(),
+		Operations: make([]string, 0, len(m.operations)),
+		Started:    m.started,
+		Finished:   finished,
+		Paths:      make([]resourcePathSummary, 0, len(m.paths)),
+	}
+	for operation := range m.operations {
+		summary.Operations = append(summary.Operations, operation)
+	}
+	sort.Strings(summary.Operations)
+
+	var durations []int64
+	for key, p := range m.paths {
+		method := strings.SplitN(key, " ", 2)
+		pathSummary := resourcePathSummary{
+			Method:      method[0],
+			Path:        method[1],
+			Calls:       p.calls,
+			Retries:     retries(p),
+			RateLimited: p.rateLimited,
+			P95Latency:  p95(p.durations),
+		}
+		summary.Paths = append(summary.Paths, pathSummary)
+		summary.Calls += pathSummary.Calls
+		summary.Retries += pathSummary.Retries
+		summary.RateLimited += pathSummary.RateLimited
+		durations = append(durations, p.durations...)
+	}
+	summary.P95Latency = p95(durations)
+
+	sort.Slice(summary.Paths, func(i, j int) bool {
+		if summary.Paths[i].Calls != summary.Paths[j].Calls {
+			return summary.Paths[i].Calls > summary.Paths[j].Calls
+		}
+		return summary.Paths[i].Method+summary.Paths[i].Path < summary.Paths[j].Method+summary.Paths[j].Path
+	})
+
+	return summary
+}
+
+func (s *metricsSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d calls, %d retries, %d rate limited, p95 latency %dms in %s by process %d (%s)\n",
+		s.Calls, s.Retries, s.RateLimited, s.P95Latency, s.Finished.Sub(s.Started).Round(time.Second), s.Pid, strings.Join(s.Operations, ", "))
+	for _, p := range s.Paths {
+		fmt.Fprintf(&b, "  %-6s %-50s calls: %-5d retries: %-5d rate limited: %-5d p95: %dms\n",
+			p.Method, p.Path, p.Calls, p.Retries, p.RateLimited, p.P95Latency)
+	}
+	return b.String()
+}
+
+// retries is the number of attempts beyond the first attempt of each call.
+// Calls that failed without any response are not reported by the SDK, so all
+// of their attempts are counted as retries.
+func retries(p *pathMetrics) int {
+	if p.attempts <= p.calls {
+		return 0
+	}
+	return p.attempts - p.calls
+}
+
+// p95 returns the 95th percentile of durations using the nearest-rank method.
+func p95(durations []int64) int64 {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := make([]int64, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := (95*len(sorted) + 99) / 100
+	return sorted[rank-1]
+}
+
+// metricsTransport records every attempt to send a request, including the
+// ones retried by the SDK.
+type metricsTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	apiMetrics.recordAttempt(req.Method, req.URL.Path, statusCode)
+
+	return resp, err
+}
@@ -0,0 +1,148 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+)
+
+func TestP95(t *testing.T) {
+	durations := make([]int64, 0, 100)
+	for i := 100; i > 0; i-- {
+		durations = append(durations, int64(i))
+	}
+
+	if p := p95(durations); p != 95 {
+		t.Fatalf("Expected p95 of 1..100 to be 95, got %d", p)
+	}
+	if p := p95([]int64{42}); p != 42 {
+		t.Fatalf("Expected p95 of a single duration to be 42, got %d", p)
+	}
+	if p := p95(nil); p != 0 {
+		t.Fatalf("Expected p95 of no durations to be 0, got %d", p)
+	}
+}
+
+func TestMetricsSummary(t *testing.T) {
+	teamId := "3b9a5a3e-3cd4-4e5a-9a5c-3d2e0d8b2b7a"
+	rateLimited := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rateLimited {
+			rateLimited = true
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"id":"%s","name":"genieteam"},"took":0.01,"requestId":"request-1"}`, teamId)
+	}))
+	defer server.Close()
+
+	apiMetrics.mu.Lock()
+	apiMetrics.paths = make(map[string]*pathMetrics)
+	apiMetrics.mu.Unlock()
+
+	config := Config{
+		ApiKey:           "key",
+		ApiUrl:           strings.TrimPrefix(server.URL, "http://"),
+		RetryCount:       2,
+		RetryWaitMin:     time.Millisecond,
+		RetryWaitMax:     time.Millisecond,
+		RetryOnRateLimit: true,
+		MetricsSummary:   true,
+	}
+	ogClient, err := config.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := team.NewClient(ogClient.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), &team.GetTeamRequest{IdentifierType: team.Id, IdentifierValue: teamId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apiMetrics.mu.Lock()
+	summary := apiMetrics.summary(time.Now())
+	apiMetrics.mu.Unlock()
+
+	if summary.Calls != 2 || summary.Retries != 1 || summary.RateLimited != 1 {
+		t.Fatalf("Expected 2 calls, 1 retry and 1 rate limited attempt, got %+v", summary)
+	}
+	if len(summary.Paths) != 1 || summary.Paths[0].Path != "/v2/teams/{id}" || summary.Paths[0].Method != http.MethodGet {
+		t.Fatalf("Expected the calls to be grouped by the path without ids, got %+v", summary.Paths)
+	}
+}
+
+func TestMetricsSummaryPath(t *testing.T) {
+	cases := map[string]string{
+		"opsgenie-metrics.json":         "opsgenie-metrics-42.json",
+		"/tmp/metrics/opsgenie.summary": "/tmp/metrics/opsgenie-42.summary",
+		"opsgenie-metrics":              "opsgenie-metrics-42",
+	}
+	for outputFile, expected := range cases {
+		if path := metricsSummaryPath(outputFile, 42); path != expected {
+			t.Errorf("Expected the summary of %s to be written to %s, got %s", outputFile, expected, path)
+		}
+	}
+}
+
+func TestMetricsCollectorFlush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "opsgenie-metrics-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	outputFile := filepath.Join(dir, "metrics.json")
+	path := metricsSummaryPath(outputFile, os.Getpid())
+
+	m := &metricsCollector{
+		enabled:    true,
+		outputFile: outputFile,
+		started:    time.Now(),
+		paths:      make(map[string]*pathMetrics),
+		operations: make(map[string]bool),
+	}
+
+	m.flush("ConfigureProvider")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected no summary to be written before any API call, got %v", err)
+	}
+
+	m.recordAttempt(http.MethodGet, "/v2/teams", http.StatusOK)
+	m.flush("ReadResource")
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := &metricsSummary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Pid != os.Getpid() || strings.Join(summary.Operations, ",") != "ConfigureProvider,ReadResource" || len(summary.Paths) != 1 {
+		t.Fatalf("Expected the summary of this process and its operations, got %+v", summary)
+	}
+
+	// the summary is only written again once more calls were made
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	m.flush("PlanResourceChange")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected the summary not to be written again without new API calls, got %v", err)
+	}
+}
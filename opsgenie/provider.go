@@ -2,12 +2,22 @@ package opsgenie
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
 )
 
+var opsGenieRegions = map[string]client.ApiUrl{
+	"us":      client.API_URL,
+	"eu":      client.API_URL_EU,
+	"sandbox": client.API_URL_SANDBOX,
+}
+
 func Provider() *schema.Provider {
 
 	p := &schema.Provider{
@@ -18,9 +28,103 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_API_KEY", nil),
 			},
 			"api_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_API_URL", nil),
+				ValidateFunc: validateApiUrl,
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_REGION", nil),
+				ValidateFunc: validation.StringInSlice([]string{"us", "eu", "sandbox"}, false),
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_SKIP_CREDENTIALS_VALIDATION", false),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_MAX_RETRIES", 10),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_RETRY_WAIT_MIN", "1s"),
+				ValidateFunc: validateDuration,
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_RETRY_WAIT_MAX", "30s"),
+				ValidateFunc: validateDuration,
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OPSGENIE_REQUEST_TIMEOUT", "0s"),
+				ValidateFunc: validateDuration,
+			},
+			"retry_on_rate_limit": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_RETRY_ON_RATE_LIMIT", true),
+			},
+			"proxy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "http",
+							ValidateFunc: validation.StringInSlice([]string{"http", "https", "socks5"}, false),
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_CA_CERT_FILE", ""),
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_INSECURE_SKIP_VERIFY", false),
+			},
+			"metrics_summary": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_METRICS_SUMMARY", false),
+			},
+			"metrics_summary_file": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_API_URL", "api.opsgenie.com"),
+				DefaultFunc: schema.EnvDefaultFunc("OPSGENIE_METRICS_SUMMARY_FILE", ""),
 			},
 		},
 
@@ -47,12 +151,16 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"opsgenie_team":       dataSourceOpsGenieTeam(),
-			"opsgenie_user":       dataSourceOpsGenieUser(),
-			"opsgenie_escalation": dataSourceOpsgenieEscalation(),
-			"opsgenie_schedule":   dataSourceOpsgenieSchedule(),
-			"opsgenie_heartbeat":  dataSourceOpsgenieHeartbeat(),
-			"opsgenie_service":    dataSourceOpsGenieService(),
+			"opsgenie_team":            dataSourceOpsGenieTeam(),
+			"opsgenie_user":            dataSourceOpsGenieUser(),
+			"opsgenie_escalation":      dataSourceOpsgenieEscalation(),
+			"opsgenie_escalation_path": dataSourceOpsgenieEscalationPath(),
+			"opsgenie_schedule":        dataSourceOpsgenieSchedule(),
+			"opsgenie_schedule_ical":   dataSourceOpsgenieScheduleIcal(),
+			"opsgenie_heartbeat":       dataSourceOpsgenieHeartbeat(),
+			"opsgenie_heartbeats":      dataSourceOpsgenieHeartbeats(),
+			"opsgenie_service":         dataSourceOpsGenieService(),
+			"opsgenie_team_logs":       dataSourceOpsGenieTeamLogs(),
 		},
 	}
 	p.ConfigureContextFunc = providerConfigure
@@ -65,12 +173,71 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 	log.Println("[INFO] Initializing OpsGenie client")
 
 	config := Config{
-		ApiKey: data.Get("api_key").(string),
-		ApiUrl: data.Get("api_url").(string),
+		ApiKey:             data.Get("api_key").(string),
+		RetryCount:         data.Get("max_retries").(int),
+		RetryOnRateLimit:   data.Get("retry_on_rate_limit").(bool),
+		ProxyConfiguration: expandOpsGenieProxyConfiguration(data.Get("proxy").([]interface{})),
+		CACertFile:         data.Get("ca_cert_file").(string),
+		InsecureSkipVerify: data.Get("insecure_skip_verify").(bool),
+		MetricsSummary:     data.Get("metrics_summary").(bool),
+		MetricsSummaryFile: data.Get("metrics_summary_file").(string),
 	}
+
+	apiUrl, err := opsGenieApiUrl(data.Get("api_url").(string), data.Get("region").(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.ApiUrl = apiUrl
+
+	// durations have already been checked by validateDuration
+	config.RetryWaitMin, _ = time.ParseDuration(data.Get("retry_wait_min").(string))
+	config.RetryWaitMax, _ = time.ParseDuration(data.Get("retry_wait_max").(string))
+	config.RequestTimeout, _ = time.ParseDuration(data.Get("request_timeout").(string))
+	if config.RetryWaitMin > config.RetryWaitMax {
+		return nil, diag.Errorf("retry_wait_min (%s) cannot be greater than retry_wait_max (%s)", config.RetryWaitMin, config.RetryWaitMax)
+	}
+
 	cli, err := config.Client()
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
+
+	if !data.Get("skip_credentials_validation").(bool) {
+		if err := cli.validateCredentials(ctx); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
 	return cli, nil
 }
+
+// opsGenieApiUrl returns the host of the API for either api_url or region,
+// defaulting to the US region.
+func opsGenieApiUrl(apiUrl, region string) (string, error) {
+	if region == "" {
+		if apiUrl == "" {
+			return string(client.API_URL), nil
+		}
+		return apiUrl, nil
+	}
+
+	regionUrl := string(opsGenieRegions[region])
+	if apiUrl != "" && apiUrl != regionUrl {
+		return "", fmt.Errorf("api_url %q does not match the API of region %q (%s), set only one of them", apiUrl, region, regionUrl)
+	}
+	return regionUrl, nil
+}
+
+func expandOpsGenieProxyConfiguration(input []interface{}) *client.ProxyConfiguration {
+	if len(input) == 0 || input[0] == nil {
+		return nil
+	}
+	inputMap := input[0].(map[string]interface{})
+
+	return &client.ProxyConfiguration{
+		Protocol: client.Protocol(inputMap["protocol"].(string)),
+		Host:     inputMap["host"].(string),
+		Port:     inputMap["port"].(int),
+		Username: inputMap["username"].(string),
+		Password: inputMap["password"].(string),
+	}
+}
@@ -1,7 +1,14 @@
 package opsgenie
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -27,3 +34,294 @@ func TestProvider(t *testing.T) {
 func TestProvider_impl(t *testing.T) {
 	var _ = Provider()
 }
+
+func TestProviderApiUrl(t *testing.T) {
+	cases := []struct {
+		apiUrl   string
+		region   string
+		expected string
+		err      bool
+	}{
+		{"", "", "api.opsgenie.com", false},
+		{"", "eu", "api.eu.opsgenie.com", false},
+		{"", "sandbox", "api.sandbox.opsgenie.com", false},
+		{"api.eu.opsgenie.com", "", "api.eu.opsgenie.com", false},
+		{"api.eu.opsgenie.com", "eu", "api.eu.opsgenie.com", false},
+		{"api.opsgenie.com", "eu", "", true},
+	}
+
+	for _, c := range cases {
+		apiUrl, err := opsGenieApiUrl(c.apiUrl, c.region)
+		if c.err != (err != nil) {
+			t.Fatalf("Expected error to be %t for api_url %q and region %q, got %v", c.err, c.apiUrl, c.region, err)
+		}
+		if apiUrl != c.expected {
+			t.Fatalf("Expected api_url %q and region %q to resolve to %q, got %q", c.apiUrl, c.region, c.expected, apiUrl)
+		}
+	}
+}
+
+func TestValidateApiUrl(t *testing.T) {
+	valid := []string{"api.opsgenie.com", "api.eu.opsgenie.com", "127.0.0.1:8080"}
+	for _, v := range valid {
+		if _, errors := validateApiUrl(v, "api_url"); len(errors) != 0 {
+			t.Fatalf("Expected %q to be a valid api_url, got %v", v, errors)
+		}
+	}
+
+	invalid := []string{"https://api.opsgenie.com", "api.opsgenie.com/v2", "api.opsgenie.com/"}
+	for _, v := range invalid {
+		if _, errors := validateApiUrl(v, "api_url"); len(errors) == 0 {
+			t.Fatalf("Expected %q to be an invalid api_url", v)
+		}
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	valid := []string{"Europe/Rome", "America/New_York", "US/Eastern", "UTC"}
+	for _, v := range valid {
+		if _, errors := validateTimezone(v, "timezone"); len(errors) != 0 {
+			t.Fatalf("Expected %q to be a valid timezone, got %v", v, errors)
+		}
+	}
+
+	invalid := []string{"", "Local", "Europe/Roma", "GMT+2", "america/new_york"}
+	for _, v := range invalid {
+		if _, errors := validateTimezone(v, "timezone"); len(errors) == 0 {
+			t.Fatalf("Expected %q to be an invalid timezone", v)
+		}
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	valid := []string{"en_US", "en_GB", "de", "pt_BR"}
+	for _, v := range valid {
+		if _, errors := validateLocale(v, "locale"); len(errors) != 0 {
+			t.Fatalf("Expected %q to be a valid locale, got %v", v, errors)
+		}
+	}
+
+	invalid := []string{"", "en-US", "en_us", "xx_XX"}
+	for _, v := range invalid {
+		if _, errors := validateLocale(v, "locale"); len(errors) == 0 {
+			t.Fatalf("Expected %q to be an invalid locale", v)
+		}
+	}
+}
+
+func TestTimeZoneLinks(t *testing.T) {
+	for alias, canonical := range timeZoneLinks {
+		if _, ok := timeZoneLinks[canonical]; ok {
+			t.Errorf("Expected %s to link to a canonical zone, got the alias %s", alias, canonical)
+		}
+		for _, name := range []string{alias, canonical} {
+			if _, err := time.LoadLocation(name); err != nil {
+				t.Errorf("Expected %s to be a time zone: %s", name, err)
+			}
+		}
+	}
+}
+
+func TestCheckTimeZoneDiff_aliases(t *testing.T) {
+	equal := [][2]string{
+		{"US/Eastern", "America/New_York"},
+		{"Europe/Rome", "Europe/Rome"},
+		{"Etc/UTC", "UTC"},
+		{"Zulu", "UTC"},
+		{"Europe/Vatican", "Europe/Rome"},
+		{"America/Vancouver", "Canada/Pacific"},
+		// zones with the same offsets all year
+		{"America/Los_Angeles", "Canada/Pacific"},
+		{"Europe/Rome", "Europe/Paris"},
+		{"Africa/Abidjan", "UTC"},
+	}
+	for _, c := range equal {
+		if !checkTimeZoneDiff("timezone", c[0], c[1], nil) {
+			t.Errorf("Expected %s and %s to be equal", c[0], c[1])
+		}
+	}
+
+	notEqual := [][2]string{
+		{"Europe/London", "Africa/Abidjan"},
+		{"America/New_York", "America/Bogota"},
+		{"", "UTC"},
+		{"Europe/Roma", "Europe/Rome"},
+	}
+	for _, c := range notEqual {
+		if checkTimeZoneDiff("timezone", c[0], c[1], nil) {
+			t.Errorf("Expected %s and %s not to be equal", c[0], c[1])
+		}
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "GenieKey valid":
+			fmt.Fprint(w, `{"data":{"name":"genietest"},"took":0.0,"requestId":"request-2"}`)
+		case "GenieKey forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You are not authorized","took":0.0,"requestId":"request-3"}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Could not authenticate","took":0.0,"requestId":"request-1"}`)
+		}
+	}))
+	defer server.Close()
+
+	for key, expected := range map[string]string{"valid": "", "invalid": "api_key is not valid", "forbidden": "api_key is not allowed"} {
+		config := Config{
+			ApiKey: key,
+			ApiUrl: strings.TrimPrefix(server.URL, "http://"),
+		}
+		cli, err := config.Client()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cli.validateCredentials(context.Background())
+		if expected == "" {
+			if err != nil {
+				t.Fatalf("Expected the api_key to be valid, got %s", err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), expected) || !strings.Contains(err.Error(), "skip_credentials_validation") {
+			t.Fatalf("Expected a %q error mentioning skip_credentials_validation for the %s key, got %v", expected, key, err)
+		}
+	}
+}
+
+func TestNormalizeResponderIdentifiers(t *testing.T) {
+	read := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"type": "user", "id": "user-id", "name": "", "username": "jane@example.com"},
+			{"type": "team", "id": "team-id", "name": "Platform", "username": ""},
+		}
+	}
+
+	cases := []struct {
+		description string
+		prior       []interface{}
+		expected    []map[string]interface{}
+	}{
+		{
+			description: "imported",
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "user-id", "name": "", "username": ""},
+				{"type": "team", "id": "team-id", "name": "", "username": ""},
+			},
+		},
+		{
+			description: "by name",
+			prior: []interface{}{
+				map[string]interface{}{"type": "user", "id": "", "name": "", "username": "Jane@example.com"},
+				map[string]interface{}{"type": "team", "id": "", "name": "Platform", "username": ""},
+			},
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "", "name": "", "username": "Jane@example.com"},
+				{"type": "team", "id": "", "name": "Platform", "username": ""},
+			},
+		},
+		{
+			description: "renamed",
+			prior: []interface{}{
+				map[string]interface{}{"type": "user", "id": "user-id", "name": "", "username": ""},
+				map[string]interface{}{"type": "team", "id": "", "name": "Infrastructure", "username": ""},
+			},
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "user-id", "name": "", "username": ""},
+				{"type": "team", "id": "", "name": "Platform", "username": ""},
+			},
+		},
+		{
+			description: "changed type",
+			prior: []interface{}{
+				map[string]interface{}{"type": "team", "id": "", "name": "Platform", "username": ""},
+			},
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "user-id", "name": "", "username": ""},
+				{"type": "team", "id": "team-id", "name": "", "username": ""},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		normalized := normalizeResponderIdentifiers(read(), c.prior)
+		if !reflect.DeepEqual(normalized, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.description, c.expected, normalized)
+		}
+	}
+}
+
+func TestNormalizeResponderSetIdentifiers(t *testing.T) {
+	read := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{"type": "user", "id": "user-id", "name": "", "username": "jane@example.com"},
+			{"type": "team", "id": "team-id", "name": "Platform", "username": ""},
+			{"type": "team", "id": "other-team-id", "name": "Database", "username": ""},
+		}
+	}
+
+	cases := []struct {
+		description string
+		prior       []interface{}
+		expected    []map[string]interface{}
+	}{
+		{
+			description: "imported",
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "user-id", "name": "", "username": ""},
+				{"type": "team", "id": "team-id", "name": "", "username": ""},
+				{"type": "team", "id": "other-team-id", "name": "", "username": ""},
+			},
+		},
+		{
+			description: "in another order",
+			prior: []interface{}{
+				map[string]interface{}{"type": "team", "id": "other-team-id", "name": "", "username": ""},
+				map[string]interface{}{"type": "team", "id": "", "name": "platform", "username": ""},
+				map[string]interface{}{"type": "user", "id": "", "name": "", "username": "Jane@example.com"},
+			},
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "", "name": "", "username": "Jane@example.com"},
+				{"type": "team", "id": "", "name": "platform", "username": ""},
+				{"type": "team", "id": "other-team-id", "name": "", "username": ""},
+			},
+		},
+		{
+			description: "renamed",
+			prior: []interface{}{
+				map[string]interface{}{"type": "team", "id": "", "name": "Infrastructure", "username": ""},
+				map[string]interface{}{"type": "team", "id": "", "name": "Database", "username": ""},
+			},
+			expected: []map[string]interface{}{
+				{"type": "user", "id": "user-id", "name": "", "username": ""},
+				{"type": "team", "id": "team-id", "name": "", "username": ""},
+				{"type": "team", "id": "", "name": "Database", "username": ""},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		normalized := normalizeResponderSetIdentifiers(read(), c.prior)
+		if !reflect.DeepEqual(normalized, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.description, c.expected, normalized)
+		}
+	}
+}
+
+func TestResponderHash(t *testing.T) {
+	configured := map[string]interface{}{"type": "team", "id": "", "name": "Platform", "username": ""}
+	normalized := normalizeResponderSetIdentifiers([]map[string]interface{}{
+		{"type": "team", "id": "team-id", "name": "platform", "username": ""},
+	}, []interface{}{configured})
+	if responderHash(normalized[0]) != responderHash(configured) {
+		t.Errorf("Expected %v to hash the same as %v", normalized[0], configured)
+	}
+
+	other := map[string]interface{}{"type": "user", "id": "", "name": "", "username": "Platform"}
+	if responderHash(other) == responderHash(configured) {
+		t.Errorf("Expected %v to hash differently than %v", other, configured)
+	}
+}
@@ -7,22 +7,26 @@ import (
 	"github.com/opsgenie/opsgenie-go-sdk-v2/alert"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"strconv"
+	"time"
 
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/policy"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
 )
 
 func resourceOpsGenieAlertPolicy() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceOpsGenieAlertPolicyCreate,
 		ReadContext:   resourceOpsGenieAlertPolicyRead,
-		Update:        resourceOpsGenieAlertPolicyUpdate,
-		Delete:        resourceOpsGenieAlertPolicyDelete,
+		UpdateContext: resourceOpsGenieAlertPolicyUpdate,
+		DeleteContext: resourceOpsGenieAlertPolicyDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), "/")
@@ -38,6 +42,17 @@ func resourceOpsGenieAlertPolicy() *schema.Resource {
 				}
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffFilter("filter"),
+			customizeDiffTimeRestriction("time_restriction"),
+			customizeDiffAlertPolicyResponders,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -237,8 +252,9 @@ func resourceOpsGenieAlertPolicy() *schema.Resource {
 				Default:  false,
 			},
 			"responders": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      responderHash,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
@@ -252,7 +268,7 @@ func resourceOpsGenieAlertPolicy() *schema.Resource {
 						},
 						"id": {
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
 						},
 						"username": {
 							Type:     schema.TypeString,
@@ -308,8 +324,8 @@ func resourceOpsGenieAlertPolicyCreate(ctx context.Context, d *schema.ResourceDa
 		AlertDescription:         alert_description,
 		Entity:                   entity,
 		Source:                   source,
-		IgnoreOriginalDetails:    &ignore_original_actions,
-		IgnoreOriginalActions:    &ignore_original_details,
+		IgnoreOriginalDetails:    &ignore_original_details,
+		IgnoreOriginalActions:    &ignore_original_actions,
 		IgnoreOriginalResponders: &ignore_original_responders,
 		IgnoreOriginalTags:       &ignore_original_tags,
 		Priority:                 alert.Priority(priority),
@@ -317,12 +333,15 @@ func resourceOpsGenieAlertPolicyCreate(ctx context.Context, d *schema.ResourceDa
 		Tags:                     flattenOpsgenieAlertPolicyTags(d),
 	}
 
-	if len(d.Get("responders").([]interface{})) > 0 {
+	if d.Get("responders").(*schema.Set).Len() > 0 {
 		createRequest.Responders = expandOpsGenieAlertPolicyResponders(d)
+		if err := resolveOpsGenieAlertPolicyResponders(ctx, meta.(*OpsgenieClient).client.Config, *createRequest.Responders); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO] Creating Alert Policy '%s'", d.Get("name").(string))
-	result, err := client.CreateAlertPolicy(context.Background(), createRequest)
+	result, err := client.CreateAlertPolicy(ctx, createRequest)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -343,22 +362,22 @@ func resourceOpsGenieAlertPolicyRead(ctx context.Context, d *schema.ResourceData
 
 	policyRes := &policy.GetAlertPolicyResult{}
 	if d.Get("team_id").(string) == "" {
-		policyRes, err = client.GetAlertPolicy(context.Background(), &policy.GetAlertPolicyRequest{
+		policyRes, err = client.GetAlertPolicy(ctx, &policy.GetAlertPolicyRequest{
 			Id: d.Id(),
 		})
 	} else {
-		policyRes, err = client.GetAlertPolicy(context.Background(), &policy.GetAlertPolicyRequest{
+		policyRes, err = client.GetAlertPolicy(ctx, &policy.GetAlertPolicyRequest{
 			Id:     d.Id(),
 			TeamId: d.Get("team_id").(string),
 		})
 	}
 	if err != nil {
-		x := err.(*ogClient.ApiError)
-		if x.StatusCode == 404 {
+		if isOpsGenieNotFound(err) {
 			log.Printf("[WARN] Removing Alert Policy because it's gone %s", name)
 			d.SetId("")
 			return nil
 		}
+		return diag.FromErr(err)
 	}
 	d.Set("name", policyRes.Name)
 	d.Set("enabled", policyRes.Enabled)
@@ -376,9 +395,10 @@ func resourceOpsGenieAlertPolicyRead(ctx context.Context, d *schema.ResourceData
 	d.Set("ignore_original_tags", policyRes.IgnoreOriginalTags)
 	d.Set("actions", policyRes.Actions)
 	d.Set("tags", policyRes.Tags)
+	d.Set("priority", policyRes.Priority)
 
 	if policyRes.Responders != nil {
-		d.Set("responders", flattenOpsGenieAlertPolicyResponders(policyRes.Responders))
+		d.Set("responders", normalizeResponderSetIdentifiers(flattenOpsGenieAlertPolicyResponders(policyRes.Responders), d.Get("responders").(*schema.Set).List()))
 	} else {
 		d.Set("responders", nil)
 	}
@@ -400,10 +420,10 @@ func resourceOpsGenieAlertPolicyRead(ctx context.Context, d *schema.ResourceData
 	return nil
 }
 
-func resourceOpsGenieAlertPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieAlertPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	message := d.Get("message").(string)
@@ -427,8 +447,8 @@ func resourceOpsGenieAlertPolicyUpdate(d *schema.ResourceData, meta interface{})
 		AlertDescription:         alert_description,
 		Entity:                   entity,
 		Source:                   source,
-		IgnoreOriginalDetails:    &ignore_original_actions,
-		IgnoreOriginalActions:    &ignore_original_details,
+		IgnoreOriginalDetails:    &ignore_original_details,
+		IgnoreOriginalActions:    &ignore_original_actions,
 		IgnoreOriginalResponders: &ignore_original_responders,
 		IgnoreOriginalTags:       &ignore_original_tags,
 		Priority:                 alert.Priority(priority),
@@ -436,24 +456,27 @@ func resourceOpsGenieAlertPolicyUpdate(d *schema.ResourceData, meta interface{})
 		Tags:                     flattenOpsgenieAlertPolicyTags(d),
 	}
 
-	if len(d.Get("responders").([]interface{})) > 0 {
+	if d.Get("responders").(*schema.Set).Len() > 0 {
 		updateRequest.Responders = expandOpsGenieAlertPolicyResponders(d)
+		if err := resolveOpsGenieAlertPolicyResponders(ctx, meta.(*OpsgenieClient).client.Config, *updateRequest.Responders); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO] Updating Alert Policy '%s'", d.Get("name").(string))
-	_, err = client.UpdateAlertPolicy(context.Background(), updateRequest)
+	_, err = client.UpdateAlertPolicy(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return nil
+	return resourceOpsGenieAlertPolicyRead(ctx, d, meta)
 }
 
-func resourceOpsGenieAlertPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieAlertPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie Alert Policy '%s'", d.Get("name").(string))
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	deleteRequest := &policy.DeletePolicyRequest{}
@@ -471,9 +494,9 @@ func resourceOpsGenieAlertPolicyDelete(d *schema.ResourceData, meta interface{})
 
 	}
 
-	_, err = client.DeletePolicy(context.Background(), deleteRequest)
+	_, err = client.DeletePolicy(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
@@ -498,13 +521,9 @@ func expandOpsGenieAlertPolicyRequestMainFields(d *schema.ResourceData) *policy.
 }
 
 func expandOpsGenieAlertPolicyResponders(d *schema.ResourceData) *[]alert.Responder {
-	input := d.Get("responders").([]interface{})
+	input := d.Get("responders").(*schema.Set).List()
 	responders := make([]alert.Responder, 0, len(input))
 
-	if input == nil {
-		return &responders
-	}
-
 	for _, v := range input {
 		config := v.(map[string]interface{})
 		responderID := config["id"].(string)
@@ -524,6 +543,70 @@ func expandOpsGenieAlertPolicyResponders(d *schema.ResourceData) *[]alert.Respon
 	return &responders
 }
 
+// resolveOpsGenieAlertPolicyResponders looks up the ids of the responders
+// referenced by name or username. The SDK rejects alert policy responders
+// without an id in policy.ValidateResponders, even though alert.Responder has
+// a name and username.
+func resolveOpsGenieAlertPolicyResponders(ctx context.Context, config *client.Config, responders []alert.Responder) error {
+	for i, responder := range responders {
+		if responder.Id != "" {
+			continue
+		}
+		switch responder.Type {
+		case alert.TeamResponder:
+			teamClient, err := team.NewClient(config)
+			if err != nil {
+				return err
+			}
+			result, err := teamClient.Get(ctx, &team.GetTeamRequest{
+				IdentifierType:  team.Name,
+				IdentifierValue: responder.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("could not find the team %q of the responders: %s", responder.Name, err)
+			}
+			responders[i].Id = result.Id
+		case alert.UserResponder:
+			userClient, err := user.NewClient(config)
+			if err != nil {
+				return err
+			}
+			result, err := userClient.Get(ctx, &user.GetRequest{Identifier: responder.Username})
+			if err != nil {
+				return fmt.Errorf("could not find the user %q of the responders: %s", responder.Username, err)
+			}
+			responders[i].Id = result.Id
+		}
+	}
+	return nil
+}
+
+// customizeDiffAlertPolicyResponders checks during plan that team responders
+// have an id or name, and user responders an id or username, to look them up
+// by.
+func customizeDiffAlertPolicyResponders(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("responders") {
+		return nil
+	}
+	for _, v := range d.Get("responders").(*schema.Set).List() {
+		responder := v.(map[string]interface{})
+		if responder["id"].(string) != "" {
+			continue
+		}
+		switch responder["type"].(string) {
+		case string(alert.TeamResponder):
+			if responder["name"].(string) == "" {
+				return fmt.Errorf("responders: team responders need an id or name")
+			}
+		case string(alert.UserResponder):
+			if responder["username"].(string) == "" {
+				return fmt.Errorf("responders: user responders need an id or username")
+			}
+		}
+	}
+	return nil
+}
+
 func expandOpsGenieAlertPolicyFilter(input []interface{}) *og.Filter {
 	filter := og.Filter{}
 
@@ -614,7 +697,7 @@ func flattenOpsGenieAlertPolicyResponders(input *[]alert.Responder) []map[string
 		element["name"] = v.Name
 		element["id"] = v.Id
 		element["username"] = v.Username
-		element["type"] = v.Type
+		element["type"] = string(v.Type)
 		output = append(output, element)
 	}
 
@@ -627,7 +710,7 @@ func flattenOpsGenieAlertPolicyFilter(input *og.Filter) []map[string]interface{}
 	if input.Conditions != nil {
 		element["conditions"] = flattenOpsGenieAlertPolicyFilterConditions(input.Conditions)
 	}
-	element["type"] = input.ConditionMatchType
+	element["type"] = string(input.ConditionMatchType)
 	output = append(output, element)
 
 	return output
@@ -637,12 +720,15 @@ func flattenOpsGenieAlertPolicyFilterConditions(input []og.Condition) []map[stri
 	output := make([]map[string]interface{}, 0, len(input))
 	for _, v := range input {
 		element := make(map[string]interface{})
-		element["field"] = v.Field
-		element["operation"] = v.Operation
+		element["field"] = string(v.Field)
+		element["operation"] = string(v.Operation)
 		element["key"] = v.Key
-		element["not"] = v.IsNot
+		element["not"] = v.IsNot != nil && *v.IsNot
 		element["expected_value"] = v.ExpectedValue
-		element["order"] = v.Order
+		element["order"] = 0
+		if v.Order != nil {
+			element["order"] = *v.Order
+		}
 		output = append(output, element)
 	}
 
@@ -655,31 +741,40 @@ func flattenOpsgenieAlertPolicyTimeRestriction(input *og.TimeRestriction) []map[
 	if len(input.RestrictionList) > 0 {
 		restrictions := make([]map[string]interface{}, 0, len(input.RestrictionList))
 		for _, r := range input.RestrictionList {
-			restrictionMap := make(map[string]interface{})
-			restrictionMap["start_min"] = r.StartMin
-			restrictionMap["start_hour"] = r.StartHour
-			restrictionMap["start_day"] = r.StartDay
-			restrictionMap["end_min"] = r.EndMin
-			restrictionMap["end_hour"] = r.EndHour
-			restrictionMap["end_day"] = r.EndDay
+			restrictionMap := flattenOpsgenieAlertPolicyRestriction(r)
+			restrictionMap["start_day"] = string(r.StartDay)
+			restrictionMap["end_day"] = string(r.EndDay)
 			restrictions = append(restrictions, restrictionMap)
 		}
 		element["restrictions"] = restrictions
 	} else {
 		restriction := make([]map[string]interface{}, 0, 1)
-		restrictionMap := make(map[string]interface{})
-		restrictionMap["start_min"] = input.Restriction.StartMin
-		restrictionMap["start_hour"] = input.Restriction.StartHour
-		restrictionMap["end_min"] = input.Restriction.EndMin
-		restrictionMap["end_hour"] = input.Restriction.EndHour
-		restriction = append(restriction, restrictionMap)
+		restriction = append(restriction, flattenOpsgenieAlertPolicyRestriction(input.Restriction))
 		element["restriction"] = restriction
 	}
-	element["type"] = input.Type
+	element["type"] = string(input.Type)
 	output = append(output, element)
 	return output
 }
 
+// flattenOpsgenieAlertPolicyRestriction returns the hours and minutes of a
+// restriction, the ones the API leaves out being 0.
+func flattenOpsgenieAlertPolicyRestriction(input og.Restriction) map[string]interface{} {
+	restrictionMap := make(map[string]interface{})
+	for k, v := range map[string]*uint32{
+		"start_hour": input.StartHour,
+		"start_min":  input.StartMin,
+		"end_hour":   input.EndHour,
+		"end_min":    input.EndMin,
+	} {
+		restrictionMap[k] = 0
+		if v != nil {
+			restrictionMap[k] = int(*v)
+		}
+	}
+	return restrictionMap
+}
+
 func flattenOpsgenieAlertPolicyTags(d *schema.ResourceData) []string {
 	input := d.Get("tags").(*schema.Set)
 	tags := make([]string, len(input.List()))
@@ -5,15 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/alert"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/policy"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
 )
 
 func init() {
@@ -25,7 +31,7 @@ func init() {
 }
 
 func testSweepAlertPolicy(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -43,7 +49,7 @@ func testSweepAlertPolicy(region string) error {
 	fmt.Println("Checking all the teams to get our TeamID")
 	for _, u := range resp.Teams {
 		fmt.Printf("checking team: %s", u.Name)
-		if strings.HasPrefix(u.Name, "genieteam") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying alert policy for team %s", u.Name)
 			client2, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 			if err != nil {
@@ -245,3 +251,283 @@ func testAccOpsGenieAlertPolicy_complete(randomTeam, randomAlertPolicyName strin
 	`, randomTeam, randomAlertPolicyName)
 
 }
+
+func TestResourceOpsGenieAlertPolicy_roundTrip(t *testing.T) {
+	cases := []struct {
+		description string
+		raw         map[string]interface{}
+	}{
+		{"defaults", map[string]interface{}{}},
+		{"main fields", map[string]interface{}{"enabled": false, "policy_description": "Routes the database alerts", "team_id": "team-id"}},
+		{"alert fields", map[string]interface{}{"continue_policy": true, "alias": "{{alias}}-db", "alert_description": "{{description}}", "entity": "database", "source": "terraform", "priority": "P2"}},
+		{"ignore original actions", map[string]interface{}{"ignore_original_actions": true}},
+		{"ignore original details", map[string]interface{}{"ignore_original_details": true}},
+		{"ignore original responders", map[string]interface{}{"ignore_original_responders": true}},
+		{"ignore original tags", map[string]interface{}{"ignore_original_tags": true}},
+		{"actions", map[string]interface{}{"actions": []interface{}{"restart", "page"}}},
+		{"tags", map[string]interface{}{"tags": []interface{}{"database", "critical"}}},
+		{"responders", map[string]interface{}{"responders": []interface{}{
+			map[string]interface{}{"type": "team", "id": "team-id"},
+			map[string]interface{}{"type": "user", "id": "user-id", "username": "jane@example.com"},
+		}}},
+		{"responders by name", map[string]interface{}{"responders": []interface{}{
+			map[string]interface{}{"type": "team", "name": "database"},
+			map[string]interface{}{"type": "user", "username": "jane@example.com"},
+		}}},
+		{"filter", map[string]interface{}{"filter": []interface{}{map[string]interface{}{
+			"type": "match-any-condition",
+			"conditions": []interface{}{
+				map[string]interface{}{"field": "tags", "operation": "contains", "expected_value": "database", "order": 1},
+				map[string]interface{}{"field": "extra-properties", "operation": "equals", "key": "env", "expected_value": "prod", "not": true, "order": 2},
+			},
+		}}}},
+		{"weekday and time of day restriction", map[string]interface{}{"time_restriction": []interface{}{map[string]interface{}{
+			"type": "weekday-and-time-of-day",
+			"restrictions": []interface{}{
+				map[string]interface{}{"start_day": "monday", "start_hour": 8, "start_min": 30, "end_day": "friday", "end_hour": 18, "end_min": 0},
+			},
+		}}}},
+		{"time of day restriction", map[string]interface{}{"time_restriction": []interface{}{map[string]interface{}{
+			"type": "time-of-day",
+			"restriction": []interface{}{
+				map[string]interface{}{"start_hour": 22, "start_min": 0, "end_hour": 6, "end_min": 30},
+			},
+		}}}},
+	}
+
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	createAlertPolicyResponders(t, meta)
+	policySchema := resourceOpsGenieAlertPolicy().Schema
+
+	for i, c := range cases {
+		c.raw["name"] = fmt.Sprintf("genie-alert-policy-%d", i)
+		c.raw["message"] = "{{message}}"
+		configured := schema.TestResourceDataRaw(t, policySchema, c.raw)
+		if diags := resourceOpsGenieAlertPolicyCreate(ctx, configured, meta); diags.HasError() {
+			t.Fatalf("%s: %v", c.description, diags)
+		}
+
+		// read the policy back from its id, keeping only what a refresh needs
+		// from the prior state
+		read := schema.TestResourceDataRaw(t, policySchema, map[string]interface{}{"team_id": c.raw["team_id"], "responders": c.raw["responders"]})
+		read.SetId(configured.Id())
+		if diags := resourceOpsGenieAlertPolicyRead(ctx, read, meta); diags.HasError() {
+			t.Fatalf("%s: %v", c.description, diags)
+		}
+
+		expected := schema.TestResourceDataRaw(t, policySchema, c.raw)
+		for k := range policySchema {
+			if !alertPolicyAttributesEqual(expected.Get(k), read.Get(k)) {
+				t.Errorf("%s: expected %s to be read back as %v, got %v", c.description, k, expected.Get(k), read.Get(k))
+			}
+		}
+	}
+}
+
+func TestResolveOpsGenieAlertPolicyResponders(t *testing.T) {
+	responders := []alert.Responder{
+		{Type: alert.TeamResponder, Name: "database"},
+		{Type: alert.UserResponder, Username: "jane@example.com"},
+		{Type: alert.TeamResponder, Id: "team-id", Name: "platform"},
+	}
+	// the API needs the ids, the names alone are rejected
+	if err := policy.ValidateResponders(&responders); err == nil || err.Error() != "responder id should be provided" {
+		t.Fatalf("Expected responders without an id to be rejected, got %v", err)
+	}
+
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	teamId, userId := createAlertPolicyResponders(t, meta)
+
+	if err := resolveOpsGenieAlertPolicyResponders(context.Background(), meta.client.Config, responders); err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{teamId, userId, "team-id"} {
+		if responders[i].Id != id {
+			t.Errorf("Expected responder %d to have id %q, got %q", i, id, responders[i].Id)
+		}
+	}
+	if err := policy.ValidateResponders(&responders); err != nil {
+		t.Errorf("Expected the resolved responders to be valid, got %s", err)
+	}
+
+	missing := []alert.Responder{{Type: alert.TeamResponder, Name: "unknown"}}
+	if err := resolveOpsGenieAlertPolicyResponders(context.Background(), meta.client.Config, missing); err == nil {
+		t.Error("Expected a responder of an unknown team to fail")
+	}
+}
+
+func TestCustomizeDiffAlertPolicyResponders(t *testing.T) {
+	cases := []struct {
+		responder map[string]interface{}
+		expected  string
+	}{
+		{map[string]interface{}{"type": "team", "id": "team-id"}, ""},
+		{map[string]interface{}{"type": "team", "name": "database"}, ""},
+		{map[string]interface{}{"type": "user", "username": "jane@example.com"}, ""},
+		{map[string]interface{}{"type": "team", "id": unknown}, ""},
+		{map[string]interface{}{"type": "user", "username": unknown}, ""},
+		{map[string]interface{}{"type": "team"}, "responders: team responders need an id or name"},
+		{map[string]interface{}{"type": "team", "username": "jane@example.com"}, "responders: team responders need an id or name"},
+		{map[string]interface{}{"type": "user", "name": "Jane"}, "responders: user responders need an id or username"},
+	}
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":       "policy",
+			"message":    "{{message}}",
+			"responders": []interface{}{c.responder},
+		})
+		_, err := resourceOpsGenieAlertPolicy().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.responder, err)
+		}
+		if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("Expected %q for %v, got %v", c.expected, c.responder, err)
+		}
+	}
+}
+
+// createAlertPolicyResponders creates the team and user that responders refer
+// to by name and username, and returns their ids.
+func createAlertPolicyResponders(t *testing.T, meta *OpsgenieClient) (string, string) {
+	ctx := context.Background()
+	teamClient, err := team.NewClient(meta.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teamResult, err := teamClient.Create(ctx, &team.CreateTeamRequest{Name: "database"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userClient, err := user.NewClient(meta.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userResult, err := userClient.Create(ctx, &user.CreateRequest{Username: "jane@example.com", FullName: "Jane", Role: &user.UserRoleRequest{RoleName: "User"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return teamResult.Id, userResult.Id
+}
+
+func TestResourceOpsGenieAlertPolicyRead_drift(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyClient, err := policy.NewClient(meta.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a policy edited in the UI, with every field changed from its default
+	enabled := false
+	yes := true
+	not := true
+	order := 1
+	startHour, startMin, endHour, endMin := uint32(9), uint32(15), uint32(17), uint32(45)
+	created, err := policyClient.CreateAlertPolicy(ctx, &policy.CreateAlertPolicyRequest{
+		MainFields: policy.MainFields{
+			PolicyType:        "alert",
+			Name:              "genie-alert-policy",
+			Enabled:           &enabled,
+			PolicyDescription: "Edited in the UI",
+			Filter: &og.Filter{
+				ConditionMatchType: og.MatchAllConditions,
+				Conditions: []og.Condition{
+					{Field: og.Message, Operation: og.Contains, ExpectedValue: "database", IsNot: &not, Order: &order},
+				},
+			},
+			TimeRestriction: &og.TimeRestriction{
+				Type:        og.TimeOfDay,
+				Restriction: og.Restriction{StartHour: &startHour, StartMin: &startMin, EndHour: &endHour, EndMin: &endMin},
+			},
+		},
+		Message:                  "{{message}} edited",
+		Continue:                 &yes,
+		Alias:                    "{{alias}}-edited",
+		AlertDescription:         "edited",
+		Entity:                   "edited-entity",
+		Source:                   "edited-source",
+		IgnoreOriginalActions:    &yes,
+		IgnoreOriginalDetails:    &yes,
+		IgnoreOriginalResponders: &yes,
+		IgnoreOriginalTags:       &yes,
+		Actions:                  []string{"restart"},
+		Tags:                     []string{"edited"},
+		Responders:               &[]alert.Responder{{Type: alert.TeamResponder, Id: "team-id"}},
+		Priority:                 alert.P1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceOpsGenieAlertPolicy().Schema, map[string]interface{}{})
+	d.SetId(created.Id)
+	if diags := resourceOpsGenieAlertPolicyRead(ctx, d, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	expected := map[string]string{
+		"name":                                 "genie-alert-policy",
+		"enabled":                              "false",
+		"policy_description":                   "Edited in the UI",
+		"message":                              "{{message}} edited",
+		"continue_policy":                      "true",
+		"alias":                                "{{alias}}-edited",
+		"alert_description":                    "edited",
+		"entity":                               "edited-entity",
+		"source":                               "edited-source",
+		"ignore_original_actions":              "true",
+		"ignore_original_details":              "true",
+		"ignore_original_responders":           "true",
+		"ignore_original_tags":                 "true",
+		"actions.#":                            "1",
+		"tags.#":                               "1",
+		"responders.#":                         "1",
+		"priority":                             "P1",
+		"filter.0.type":                        "match-all-conditions",
+		"filter.0.conditions.0.field":          "message",
+		"filter.0.conditions.0.operation":      "contains",
+		"filter.0.conditions.0.expected_value": "database",
+		"filter.0.conditions.0.not":            "true",
+		"filter.0.conditions.0.order":          "1",
+		"time_restriction.0.type":              "time-of-day",
+		"time_restriction.0.restriction.0.start_hour": "9",
+		"time_restriction.0.restriction.0.start_min":  "15",
+		"time_restriction.0.restriction.0.end_hour":   "17",
+		"time_restriction.0.restriction.0.end_min":    "45",
+	}
+	attributes := d.State().Attributes
+	for k, v := range expected {
+		if attributes[k] != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, attributes[k])
+		}
+	}
+	if responders := d.Get("responders").(*schema.Set).List(); len(responders) != 1 || responders[0].(map[string]interface{})["id"] != "team-id" {
+		t.Errorf("Expected the team responder to be read by id, got %v", responders)
+	}
+}
+
+// alertPolicyAttributesEqual compares the values of an attribute, sets
+// regardless of their order.
+func alertPolicyAttributesEqual(expected, actual interface{}) bool {
+	if expectedSet, ok := expected.(*schema.Set); ok {
+		actualSet, ok := actual.(*schema.Set)
+		return ok && expectedSet.Equal(actualSet)
+	}
+	return reflect.DeepEqual(expected, actual)
+}
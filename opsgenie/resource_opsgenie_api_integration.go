@@ -2,7 +2,9 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -12,12 +14,18 @@ import (
 
 func resourceOpsgenieApiIntegration() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieApiIntegrationCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieApiIntegrationRead),
-		Update: resourceOpsgenieApiIntegrationUpdate,
-		Delete: resourceOpsgenieApiIntegrationDelete,
+		CreateContext: resourceOpsgenieApiIntegrationCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieApiIntegrationRead),
+		UpdateContext: resourceOpsgenieApiIntegrationUpdate,
+		DeleteContext: resourceOpsgenieApiIntegrationDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -75,6 +83,16 @@ func resourceOpsgenieApiIntegration() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the team, schedule or escalation, instead of id",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username of the user, instead of id",
+						},
 					},
 				},
 			},
@@ -89,12 +107,12 @@ func resourceOpsgenieApiIntegration() *schema.Resource {
 	}
 }
 
-func resourceOpsgenieApiIntegrationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieApiIntegrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	integrationType := d.Get("type").(string)
 	if integrationType == WebhookIntegrationType {
-		return createWebhookIntegration(d, meta)
+		return diag.FromErr(createWebhookIntegration(ctx, d, meta))
 	}
-	return createApiIntegration(d, meta)
+	return diag.FromErr(createApiIntegration(ctx, d, meta))
 }
 
 func expandOpsGenieWebhookHeaders(d *schema.ResourceData) map[string]string {
@@ -112,7 +130,7 @@ func expandOpsGenieWebhookHeaders(d *schema.ResourceData) map[string]string {
 	return output
 }
 
-func createApiIntegration(d *schema.ResourceData, meta interface{}) error {
+func createApiIntegration(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -150,7 +168,7 @@ func createApiIntegration(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[INFO] Creating OpsGenie api integration '%s'", name)
 
-	result, err := client.CreateApiBased(context.Background(), createRequest)
+	result, err := client.CreateApiBased(ctx, createRequest)
 	if err != nil {
 		return err
 	}
@@ -159,7 +177,7 @@ func createApiIntegration(d *schema.ResourceData, meta interface{}) error {
 	d.Set("api_key", result.ApiKey)
 
 	if enabled {
-		_, err = client.Enable(context.Background(), &integration.EnableIntegrationRequest{
+		_, err = client.Enable(ctx, &integration.EnableIntegrationRequest{
 			Id: result.Id,
 		})
 		if err != nil {
@@ -169,10 +187,10 @@ func createApiIntegration(d *schema.ResourceData, meta interface{}) error {
 
 	}
 
-	return resourceOpsgenieApiIntegrationRead(d, meta)
+	return resourceOpsgenieApiIntegrationRead(ctx, d, meta)
 }
 
-func createWebhookIntegration(d *schema.ResourceData, meta interface{}) error {
+func createWebhookIntegration(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -204,7 +222,7 @@ func createWebhookIntegration(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[INFO] Creating OpsGenie Webhook integration '%s'", name)
 
-	result, err := client.CreateWebhook(context.Background(), createRequest)
+	result, err := client.CreateWebhook(ctx, createRequest)
 	if err != nil {
 		return err
 	}
@@ -213,7 +231,7 @@ func createWebhookIntegration(d *schema.ResourceData, meta interface{}) error {
 	d.Set("api_key", result.ApiKey)
 
 	if enabled {
-		_, err = client.Enable(context.Background(), &integration.EnableIntegrationRequest{
+		_, err = client.Enable(ctx, &integration.EnableIntegrationRequest{
 			Id: result.Id,
 		})
 		if err != nil {
@@ -222,16 +240,16 @@ func createWebhookIntegration(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[INFO] Enabled OpsGenie Webhook integration '%s'", name)
 	}
 
-	return resourceOpsgenieApiIntegrationRead(d, meta)
+	return resourceOpsgenieApiIntegrationRead(ctx, d, meta)
 }
 
-func resourceOpsgenieApiIntegrationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieApiIntegrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
 	}
 
-	result, err := client.Get(context.Background(), &integration.GetRequest{
+	result, err := client.Get(ctx, &integration.GetRequest{
 		Id: d.Id(),
 	})
 	if err != nil {
@@ -242,7 +260,7 @@ func resourceOpsgenieApiIntegrationRead(d *schema.ResourceData, meta interface{}
 		ownerTeam := result.Data["ownerTeam"].(map[string]interface{})
 		d.Set("owner_team_id", ownerTeam["id"])
 	} else if result.Data["responders"] != nil {
-		d.Set("responders", flattenIntegrationResponders(result.Data["responders"].([]interface{})))
+		d.Set("responders", normalizeResponderIdentifiers(flattenIntegrationResponders(result.Data["responders"].([]interface{})), d.Get("responders").([]interface{})))
 	}
 	d.Set("name", result.Data["name"])
 	d.Set("type", result.Data["type"])
@@ -261,19 +279,19 @@ func resourceOpsgenieApiIntegrationRead(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
-func resourceOpsgenieApiIntegrationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieApiIntegrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// GET+PUT workaround since the Opsgenie Integration API does not support HTTP PATCH method
-	result, err := client.Get(context.Background(), &integration.GetRequest{
+	result, err := client.Get(ctx, &integration.GetRequest{
 		Id: d.Id(),
 	})
 	if err != nil {
 		log.Printf("Error occurred while performing GET for integration: %s", d.Id())
-		return err
+		return diag.FromErr(err)
 	}
 
 	userProperties := result.Data
@@ -312,27 +330,27 @@ func resourceOpsgenieApiIntegrationUpdate(d *schema.ResourceData, meta interface
 
 	log.Printf("[INFO] Updating OpsGenie api based integration '%s'", name)
 
-	_, err = client.ForceUpdateAllFields(context.Background(), updateRequest)
+	_, err = client.ForceUpdateAllFields(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsgenieApiIntegrationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieApiIntegrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie api integration '%s'", d.Get("name").(string))
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &integration.DeleteIntegrationRequest{
 		Id: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
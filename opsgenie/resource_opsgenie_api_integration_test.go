@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -18,13 +17,16 @@ import (
 func init() {
 	resource.AddTestSweepers("opsgenie_api_integration", &resource.Sweeper{
 		Name: "opsgenie_api_integration",
-		F:    testSweepApiIntegration,
+		Dependencies: []string{
+			"opsgenie_integration_action",
+		},
+		F: testSweepApiIntegration,
 	})
 
 }
 
 func testSweepApiIntegration(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +41,7 @@ func testSweepApiIntegration(region string) error {
 	}
 
 	for _, u := range resp.Integrations {
-		if strings.HasPrefix(u.Name, "genieintegration-") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying integration %s", u.Name)
 
 			deleteRequest := integration.DeleteIntegrationRequest{
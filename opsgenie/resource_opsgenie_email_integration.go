@@ -2,7 +2,9 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"time"
 
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 
@@ -13,12 +15,18 @@ import (
 
 func resourceOpsgenieEmailIntegration() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieEmailIntegrationCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieEmailIntegrationRead),
-		Update: resourceOpsgenieEmailIntegrationUpdate,
-		Delete: resourceOpsgenieEmailIntegrationDelete,
+		CreateContext: resourceOpsgenieEmailIntegrationCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieEmailIntegrationRead),
+		UpdateContext: resourceOpsgenieEmailIntegrationUpdate,
+		DeleteContext: resourceOpsgenieEmailIntegrationDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -61,6 +69,16 @@ func resourceOpsgenieEmailIntegration() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the team, schedule or escalation, instead of id",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username of the user, instead of id",
+						},
 					},
 				},
 			},
@@ -68,10 +86,10 @@ func resourceOpsgenieEmailIntegration() *schema.Resource {
 	}
 }
 
-func resourceOpsgenieEmailIntegrationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEmailIntegrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	emailUsername := d.Get("email_username").(string)
@@ -97,34 +115,34 @@ func resourceOpsgenieEmailIntegrationCreate(d *schema.ResourceData, meta interfa
 
 	log.Printf("[INFO] Creating OpsGenie email integration '%s'", name)
 
-	result, err := client.CreateEmailBased(context.Background(), createRequest)
+	result, err := client.CreateEmailBased(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
 	if enabled {
-		_, err = client.Enable(context.Background(), &integration.EnableIntegrationRequest{
+		_, err = client.Enable(ctx, &integration.EnableIntegrationRequest{
 			Id: result.Id,
 		})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		log.Printf("[INFO] Enabled OpsGenie email integration '%s'", name)
 
 	}
 
-	return resourceOpsgenieEmailIntegrationRead(d, meta)
+	return diag.FromErr(resourceOpsgenieEmailIntegrationRead(ctx, d, meta))
 }
 
-func resourceOpsgenieEmailIntegrationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEmailIntegrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
 	}
 
-	result, err := client.Get(context.Background(), &integration.GetRequest{
+	result, err := client.Get(ctx, &integration.GetRequest{
 		Id: d.Id(),
 	})
 	if err != nil {
@@ -135,7 +153,7 @@ func resourceOpsgenieEmailIntegrationRead(d *schema.ResourceData, meta interface
 		ownerTeam := result.Data["ownerTeam"].(map[string]interface{})
 		d.Set("owner_team_id", ownerTeam["id"])
 	} else if result.Data["responders"] != nil {
-		d.Set("responders", flattenIntegrationResponders(result.Data["responders"].([]interface{})))
+		d.Set("responders", normalizeResponderIdentifiers(flattenIntegrationResponders(result.Data["responders"].([]interface{})), d.Get("responders").([]interface{})))
 	}
 	d.Set("name", result.Data["name"])
 	d.Set("suppress_notifications", result.Data["suppressNotifications"])
@@ -144,10 +162,10 @@ func resourceOpsgenieEmailIntegrationRead(d *schema.ResourceData, meta interface
 	return nil
 }
 
-func resourceOpsgenieEmailIntegrationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEmailIntegrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	emailUsername := d.Get("email_username").(string)
@@ -168,27 +186,27 @@ func resourceOpsgenieEmailIntegrationUpdate(d *schema.ResourceData, meta interfa
 
 	log.Printf("[INFO] Updating OpsGenie email based integration '%s'", name)
 
-	_, err = client.ForceUpdateAllFields(context.Background(), updateRequest)
+	_, err = client.ForceUpdateAllFields(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsgenieEmailIntegrationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEmailIntegrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie email integration '%s'", d.Get("name").(string))
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &integration.DeleteIntegrationRequest{
 		Id: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -18,13 +17,16 @@ import (
 func init() {
 	resource.AddTestSweepers("opsgenie_email_integration", &resource.Sweeper{
 		Name: "opsgenie_email_integration",
-		F:    testSweepEmailIntegration,
+		Dependencies: []string{
+			"opsgenie_integration_action",
+		},
+		F: testSweepEmailIntegration,
 	})
 
 }
 
 func testSweepEmailIntegration(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +41,7 @@ func testSweepEmailIntegration(region string) error {
 	}
 
 	for _, u := range resp.Integrations {
-		if strings.HasPrefix(u.Name, "genieintegration-") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying integration %s", u.Name)
 
 			deleteRequest := integration.DeleteIntegrationRequest{
@@ -3,8 +3,11 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 
@@ -14,12 +17,19 @@ import (
 
 func resourceOpsgenieEscalation() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieEscalationCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieEscalationRead),
-		Update: resourceOpsgenieEscalationUpdate,
-		Delete: resourceOpsgenieEscalationDelete,
+		CreateContext: resourceOpsgenieEscalationCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieEscalationRead),
+		UpdateContext: resourceOpsgenieEscalationUpdate,
+		DeleteContext: resourceOpsgenieEscalationDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceOpsgenieEscalationImport,
+		},
+		CustomizeDiff: customizeDiffEscalationRules("rules"),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -31,43 +41,10 @@ func resourceOpsgenieEscalation() *schema.Resource {
 				Optional: true,
 			},
 			"rules": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"condition": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateOpsgenieEscalationRulesCondition,
-						},
-						"notify_type": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateOpsgenieEscalationRulesNotifyType,
-						},
-						"recipient": {
-							Type:     schema.TypeList,
-							Required: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"type": {
-										Type:         schema.TypeString,
-										Optional:     true,
-										ValidateFunc: validateEscalationParticipantType,
-									},
-									"id": {
-										Type:     schema.TypeString,
-										Optional: true,
-									},
-								},
-							},
-						},
-						"delay": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-					},
-				},
+				Elem:     resourceOpsgenieEscalationRule(),
+				Set:      escalationRuleHash,
 			},
 			"owner_team_id": {
 				Type:     schema.TypeString,
@@ -76,45 +53,101 @@ func resourceOpsgenieEscalation() *schema.Resource {
 			"repeat": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Elem:     resourceOpsgenieEscalationRepeat(),
+			},
+		},
+	}
+}
+
+func resourceOpsgenieEscalationRule() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"condition": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateOpsgenieEscalationRulesCondition,
+			},
+			"notify_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateOpsgenieEscalationRulesNotifyType,
+			},
+			"recipient": {
+				Type:     schema.TypeList,
+				Required: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"wait_interval": {
-							Type:     schema.TypeInt,
-							Optional: true,
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateEscalationParticipantType,
 						},
-						"count": {
-							Type:     schema.TypeInt,
+						"id": {
+							Type:     schema.TypeString,
 							Optional: true,
 						},
-						"reset_recipient_states": {
-							Type:     schema.TypeBool,
-							Optional: true,
+						"name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the team or schedule, instead of id",
 						},
-						"close_alert_after_all": {
-							Type:     schema.TypeBool,
-							Optional: true,
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username of the user, instead of id",
 						},
 					},
 				},
 			},
+			"delay": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
 		},
 	}
 }
 
-func resourceOpsgenieEscalationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEscalationRepeat() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"wait_interval": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"count": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"reset_recipient_states": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"close_alert_after_all": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceOpsgenieEscalationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
 	ownerTeam := d.Get("owner_team_id").(string)
 
+	if err := validateEscalationRules("rules", d.Get("rules").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	createRequest := &escalation.CreateRequest{
 		Name:        name,
 		Description: description,
-		Rules:       expandOpsgenieEscalationRules(d),
-		Repeat:      expandOpsgenieEscalationRepeat(d),
+		Rules:       expandOpsgenieEscalationRuleSet(d.Get("rules").(*schema.Set)),
+		Repeat:      expandOpsgenieEscalationRepeat(d.Get("repeat").([]interface{})),
 	}
 
 	if ownerTeam != "" {
@@ -125,17 +158,39 @@ func resourceOpsgenieEscalationCreate(d *schema.ResourceData, meta interface{})
 
 	log.Printf("[INFO] Creating OpsGenie escalation '%s'", name)
 
-	result, err := client.Create(context.Background(), createRequest)
+	result, err := client.Create(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsgenieEscalationRead(d, meta)
+	return diag.FromErr(resourceOpsgenieEscalationRead(ctx, d, meta))
 }
 
-func resourceOpsgenieEscalationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEscalationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, ok := importName(d.Id())
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &escalation.GetRequest{
+		IdentifierType: escalation.Name,
+		Identifier:     name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not find escalation %q to import: %s", name, err)
+	}
+
+	d.SetId(result.Id)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceOpsgenieEscalationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -146,14 +201,14 @@ func resourceOpsgenieEscalationRead(d *schema.ResourceData, meta interface{}) er
 		Identifier:     d.Id(),
 	}
 
-	getResponse, err := client.Get(context.Background(), getRequest)
+	getResponse, err := client.Get(ctx, getRequest)
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", getResponse.Name)
 	d.Set("description", getResponse.Description)
-	d.Set("rules", flattenOpsgenieEscalationRules(getResponse.Rules))
+	d.Set("rules", normalizeEscalationRuleRecipients(flattenOpsgenieEscalationRules(getResponse.Rules), d.Get("rules").(*schema.Set).List()))
 	repeat := d.Get("repeat").([]interface{})
 	if len(repeat) > 0 {
 		d.Set("repeat", flattenOpsgenieEscalationRepeat(getResponse.Repeat))
@@ -165,22 +220,26 @@ func resourceOpsgenieEscalationRead(d *schema.ResourceData, meta interface{}) er
 	return nil
 }
 
-func resourceOpsgenieEscalationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEscalationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
 	ownerTeam := d.Get("owner_team_id").(string)
 
+	if err := validateEscalationRules("rules", d.Get("rules").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	updateRequest := &escalation.UpdateRequest{
 		IdentifierType: escalation.Id,
 		Identifier:     d.Id(),
 		Name:           name,
 		Description:    description,
-		Rules:          expandOpsgenieEscalationRules(d),
-		Repeat:         expandOpsgenieEscalationRepeat(d),
+		Rules:          expandOpsgenieEscalationRuleSet(d.Get("rules").(*schema.Set)),
+		Repeat:         expandOpsgenieEscalationRepeat(d.Get("repeat").([]interface{})),
 	}
 	if ownerTeam != "" {
 		updateRequest.OwnerTeam = &og.OwnerTeam{
@@ -191,28 +250,28 @@ func resourceOpsgenieEscalationUpdate(d *schema.ResourceData, meta interface{})
 	}
 	log.Printf("[INFO] Updating OpsGenie escalation '%s'", name)
 
-	_, err = client.Update(context.Background(), updateRequest)
+	_, err = client.Update(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsgenieEscalationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieEscalationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie escalation '%s'", d.Get("name").(string))
 	client, err := escalation.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &escalation.DeleteRequest{
 		IdentifierType: escalation.Id,
 		Identifier:     d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -228,6 +287,8 @@ func flattenOpsgenieEscalationRules(input []escalation.Rule) []map[string]interf
 		recipientArr := make([]map[string]interface{}, 0, 1)
 		recipient := make(map[string]interface{})
 		recipient["id"] = rule.Recipient.Id
+		recipient["name"] = rule.Recipient.Name
+		recipient["username"] = rule.Recipient.Username
 		recipient["type"] = rule.Recipient.Type
 		recipientArr = append(recipientArr, recipient)
 		out["recipient"] = recipientArr
@@ -237,6 +298,102 @@ func flattenOpsgenieEscalationRules(input []escalation.Rule) []map[string]interf
 	return rules
 }
 
+// normalizeEscalationRuleRecipients references the recipients of rules the
+// same way as the rules in prior with the same delay, see
+// normalizeResponderIdentifiers.
+func normalizeEscalationRuleRecipients(rules []map[string]interface{}, prior []interface{}) []map[string]interface{} {
+	for _, rule := range rules {
+		var priorRecipient []interface{}
+		for _, p := range prior {
+			if priorRule, ok := p.(map[string]interface{}); ok && fmt.Sprint(priorRule["delay"]) == fmt.Sprint(rule["delay"]) {
+				priorRecipient, _ = priorRule["recipient"].([]interface{})
+				break
+			}
+		}
+		rule["recipient"] = normalizeResponderIdentifiers(rule["recipient"].([]map[string]interface{}), priorRecipient)
+	}
+	return rules
+}
+
+// escalationRuleHash identifies rules by their delay and recipient, so that
+// adding or removing a rule does not show the other rules as changed.
+func escalationRuleHash(v interface{}) int {
+	rule := v.(map[string]interface{})
+
+	recipient := ""
+	switch r := rule["recipient"].(type) {
+	case []interface{}:
+		if len(r) > 0 && r[0] != nil {
+			recipient = responderKey(r[0].(map[string]interface{}))
+		}
+	case []map[string]interface{}:
+		if len(r) > 0 {
+			recipient = responderKey(r[0])
+		}
+	}
+
+	return schema.HashString(fmt.Sprintf("%v-%s", rule["delay"], recipient))
+}
+
+// escalationNotifyTypes are the notify types that only apply to some recipient
+// types. The other notify types apply to all of them.
+var escalationNotifyTypes = map[string][]string{
+	"next":     {"schedule"},
+	"previous": {"schedule"},
+	"users":    {"team"},
+	"admins":   {"team"},
+	"random":   {"team"},
+	"all":      {"team"},
+}
+
+// customizeDiffEscalationRules validates the set of escalation rules at key
+// during plan.
+func customizeDiffEscalationRules(key string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+		rules, ok := d.Get(key).(*schema.Set)
+		if !ok {
+			return nil
+		}
+		// During plan the SDK reads the recipients of rules in a set as
+		// empty, so rules with the same delay are read as one rule. The
+		// number of rules is read as planned and tells them apart. The
+		// notify types are checked again before the rules are sent.
+		if n, ok := d.Get(key + ".#").(int); ok && n > rules.Len() {
+			return fmt.Errorf("%s: more than one rule has the same delay, the delays of rules must be unique", key)
+		}
+		return validateEscalationRules(key, rules.List())
+	}
+}
+
+// validateEscalationRules rejects rules that the API would reject or
+// silently merge: rules with the same delay, and notify types that do not
+// apply to the recipient type.
+func validateEscalationRules(key string, rules []interface{}) error {
+	delays := make(map[int]bool, len(rules))
+	for _, r := range rules {
+		rule := r.(map[string]interface{})
+		delay := rule["delay"].(int)
+		if delays[delay] {
+			return fmt.Errorf("%s: more than one rule has delay %d, the delays of rules must be unique", key, delay)
+		}
+		delays[delay] = true
+
+		recipients := rule["recipient"].([]interface{})
+		if len(recipients) == 0 || recipients[0] == nil {
+			continue
+		}
+		recipientType := strings.ToLower(recipients[0].(map[string]interface{})["type"].(string))
+		notifyType := strings.ToLower(rule["notify_type"].(string))
+		if allowed, ok := escalationNotifyTypes[notifyType]; ok && recipientType != "" && !stringInSlice(recipientType, allowed) {
+			return fmt.Errorf("%s: the rule with delay %d has notify_type %q, which only applies to %s recipients, not %s", key, delay, notifyType, strings.Join(allowed, ", "), recipientType)
+		}
+	}
+	return nil
+}
+
 func flattenOpsgenieEscalationRepeat(input escalation.Repeat) []map[string]interface{} {
 	repeats := make([]map[string]interface{}, 0, 1)
 	out := make(map[string]interface{})
@@ -248,9 +405,16 @@ func flattenOpsgenieEscalationRepeat(input escalation.Repeat) []map[string]inter
 	return repeats
 }
 
-func expandOpsgenieEscalationRules(d *schema.ResourceData) []escalation.RuleRequest {
-	input := d.Get("rules").([]interface{})
+// expandOpsgenieEscalationRuleSet expands rules in the order of their delay.
+func expandOpsgenieEscalationRuleSet(input *schema.Set) []escalation.RuleRequest {
+	rules := input.List()
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].(map[string]interface{})["delay"].(int) < rules[j].(map[string]interface{})["delay"].(int)
+	})
+	return expandOpsgenieEscalationRules(rules)
+}
 
+func expandOpsgenieEscalationRules(input []interface{}) []escalation.RuleRequest {
 	rules := make([]escalation.RuleRequest, 0, len(input))
 	if input == nil {
 		return rules
@@ -284,14 +448,14 @@ func expandOpsgenieEscalationRuleParticipant(pp []interface{}) og.Participant {
 		p := b.(map[string]interface{})
 		participant.Type = og.ParticipantType(p["type"].(string))
 
-		id := p["id"]
-		participant.Id = id.(string)
+		participant.Id = p["id"].(string)
+		participant.Name = p["name"].(string)
+		participant.Username = p["username"].(string)
 	}
 	return participant
 }
 
-func expandOpsgenieEscalationRepeat(d *schema.ResourceData) *escalation.RepeatRequest {
-	input := d.Get("repeat").([]interface{})
+func expandOpsgenieEscalationRepeat(input []interface{}) *escalation.RepeatRequest {
 	repeat := escalation.RepeatRequest{}
 	for _, r := range input {
 		repeatMap := r.(map[string]interface{})
@@ -10,21 +10,26 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
 )
 
 func init() {
 	resource.AddTestSweepers("opsgenie_escalation", &resource.Sweeper{
 		Name: "opsgenie_escalation",
-		F:    testSweepEscalation,
+		Dependencies: []string{
+			"opsgenie_team_routing_rule",
+		},
+		F: testSweepEscalation,
 	})
 
 }
 
 func testSweepEscalation(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,8 +44,8 @@ func testSweepEscalation(region string) error {
 	}
 
 	for _, u := range resp.Escalations {
-		if strings.HasPrefix(u.Description, "genieescalation-") {
-			log.Printf("Destroying escalation %s", u.Description)
+		if isSweepable(u.Name) {
+			log.Printf("Destroying escalation %s", u.Name)
 
 			deleteRequest := escalation.DeleteRequest{
 				IdentifierType: escalation.Id,
@@ -72,6 +77,46 @@ func TestAccOpsGenieEscalation_basic(t *testing.T) {
 					testCheckOpsGenieEscalationExists("opsgenie_escalation.test"),
 				),
 			},
+			{
+				ResourceName:      "opsgenie_escalation.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("name:genieescalation-%s", randomEscalation),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccOpsGenieEscalation_recipientByName(t *testing.T) {
+	randomName := acctest.RandString(6)
+	randomTeam := acctest.RandString(6)
+	randomEscalation := acctest.RandString(6)
+
+	config := testAccOpsGenieEscalation_recipientByName(randomName, randomTeam, randomEscalation)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testCheckOpsGenieEscalationDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckOpsGenieEscalationExists("opsgenie_escalation.test"),
+					resource.TestCheckTypeSetElemNestedAttrs("opsgenie_escalation.test", "rules.*", map[string]string{
+						"delay":                "1",
+						"recipient.0.username": fmt.Sprintf("genietest-%s@opsgenie.com", randomName),
+						"recipient.0.id":       "",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("opsgenie_escalation.test", "rules.*", map[string]string{
+						"delay":            "5",
+						"recipient.0.name": fmt.Sprintf("genieteam-%s", randomTeam),
+					}),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
 		},
 	})
 }
@@ -174,6 +219,41 @@ resource "opsgenie_escalation" "test" {
 `, randomName, randomEscalation)
 }
 
+func testAccOpsGenieEscalation_recipientByName(randomName, randomTeam, randomEscalation string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_user" "test" {
+  username  = "genietest-%s@opsgenie.com"
+  full_name = "Acceptance Test User"
+  role      = "User"
+}
+resource "opsgenie_team" "test" {
+  name        = "genieteam-%s"
+  description = "This team deals with all the things"
+}
+resource "opsgenie_escalation" "test" {
+  name = "genieescalation-%s"
+  rules {
+    condition   = "if-not-acked"
+    notify_type = "default"
+    recipient {
+      type     = "user"
+      username = opsgenie_user.test.username
+    }
+    delay = 1
+  }
+  rules {
+    condition   = "if-not-acked"
+    notify_type = "default"
+    recipient {
+      type = "team"
+      name = opsgenie_team.test.name
+    }
+    delay = 5
+  }
+}
+`, randomName, randomTeam, randomEscalation)
+}
+
 func testAccOpsGenieEscalation_complete(randomTeam, randomSchedule, randomEscalation string) string {
 	return fmt.Sprintf(`
 resource "opsgenie_team" "test" {
@@ -208,3 +288,117 @@ repeat  {
 }
 `, randomTeam, randomSchedule, randomEscalation)
 }
+
+func testEscalationRule(delay int, notifyType, recipientType string) map[string]interface{} {
+	return map[string]interface{}{
+		"condition":   "if-not-acked",
+		"notify_type": notifyType,
+		"delay":       delay,
+		"recipient": []interface{}{
+			map[string]interface{}{"type": recipientType, "id": "recipient-id", "name": "", "username": ""},
+		},
+	}
+}
+
+func TestEscalationRuleHash(t *testing.T) {
+	rule := testEscalationRule(5, "default", "schedule")
+
+	changedCondition := testEscalationRule(5, "default", "schedule")
+	changedCondition["condition"] = "if-not-closed"
+	if escalationRuleHash(rule) != escalationRuleHash(changedCondition) {
+		t.Errorf("Expected rules with the same delay and recipient to have the same hash")
+	}
+
+	if escalationRuleHash(rule) == escalationRuleHash(testEscalationRule(10, "default", "schedule")) {
+		t.Errorf("Expected rules with different delays to have different hashes")
+	}
+	if escalationRuleHash(rule) == escalationRuleHash(testEscalationRule(5, "default", "team")) {
+		t.Errorf("Expected rules with different recipients to have different hashes")
+	}
+}
+
+func TestValidateEscalationRules(t *testing.T) {
+	cases := []struct {
+		rules    []interface{}
+		expected string
+	}{
+		{
+			rules: []interface{}{
+				testEscalationRule(0, "default", "user"),
+				testEscalationRule(5, "next", "schedule"),
+				testEscalationRule(10, "admins", "team"),
+			},
+		},
+		{
+			rules: []interface{}{
+				testEscalationRule(5, "default", "user"),
+				testEscalationRule(5, "default", "team"),
+			},
+			expected: "rules: more than one rule has delay 5, the delays of rules must be unique",
+		},
+		{
+			rules:    []interface{}{testEscalationRule(0, "next", "team")},
+			expected: `rules: the rule with delay 0 has notify_type "next", which only applies to schedule recipients, not team`,
+		},
+		{
+			rules:    []interface{}{testEscalationRule(0, "all", "user")},
+			expected: `rules: the rule with delay 0 has notify_type "all", which only applies to team recipients, not user`,
+		},
+	}
+
+	for _, c := range cases {
+		err := validateEscalationRules("rules", c.rules)
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.rules, err)
+		}
+		if c.expected != "" && (err == nil || err.Error() != c.expected) {
+			t.Errorf("Expected %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestCustomizeDiffEscalationRules(t *testing.T) {
+	cases := []struct {
+		rules    []interface{}
+		expected string
+	}{
+		{
+			rules: []interface{}{testEscalationRule(0, "default", "user"), testEscalationRule(5, "next", "schedule")},
+		},
+		{
+			rules:    []interface{}{testEscalationRule(5, "default", "user"), testEscalationRule(5, "default", "team")},
+			expected: "rules: more than one rule has the same delay, the delays of rules must be unique",
+		},
+	}
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "escalation", "rules": c.rules})
+		_, err := resourceOpsgenieEscalation().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.rules, err)
+		}
+		if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("Expected %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestResourceOpsgenieEscalationCreate_notifyType(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceOpsgenieEscalation().Schema, map[string]interface{}{
+		"name":  "escalation",
+		"rules": []interface{}{testEscalationRule(0, "all", "user")},
+	})
+	expected := `rules: the rule with delay 0 has notify_type "all", which only applies to team recipients, not user`
+	if diags := resourceOpsgenieEscalationCreate(context.Background(), d, meta); !diags.HasError() || diags[0].Summary != expected {
+		t.Errorf("Expected %q, got %v", expected, diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("Expected the escalation not to be created, got %s", d.Id())
+	}
+}
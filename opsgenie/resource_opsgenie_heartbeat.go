@@ -3,21 +3,30 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/heartbeat"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 )
 
 func resourceOpsgenieHeartbeat() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieHeartbeatCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieHeartbeatRead),
-		Update: resourceOpsgenieHeartbeatUpdate,
-		Delete: resourceOpsgenieHeartbeatDelete,
+		CreateContext: resourceOpsgenieHeartbeatCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieHeartbeatRead),
+		UpdateContext: resourceOpsgenieHeartbeatUpdate,
+		DeleteContext: resourceOpsgenieHeartbeatDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -33,10 +42,18 @@ func resourceOpsgenieHeartbeat() *schema.Resource {
 			"interval": {
 				Type:     schema.TypeInt,
 				Required: true,
+				// the API documents no upper bound, the SDK rejects intervals
+				// smaller than 1 in heartbeat.AddRequest.Validate
+				ValidateFunc:     validation.IntAtLeast(1),
+				DiffSuppressFunc: suppressEquivalentHeartbeatInterval,
 			},
 			"interval_unit": {
 				Type:     schema.TypeString,
 				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(heartbeat.Minutes), string(heartbeat.Hours), string(heartbeat.Days),
+				}, false),
+				DiffSuppressFunc: suppressEquivalentHeartbeatInterval,
 			},
 			"enabled": {
 				Type:     schema.TypeBool,
@@ -59,17 +76,18 @@ func resourceOpsgenieHeartbeat() *schema.Resource {
 				Set: schema.HashString,
 			},
 			"alert_priority": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"P1", "P2", "P3", "P4", "P5"}, false),
 			},
 		},
 	}
 }
 
-func resourceOpsgenieHeartbeatCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieHeartbeatCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := heartbeat.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -96,23 +114,23 @@ func resourceOpsgenieHeartbeatCreate(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	result, err := client.Add(context.Background(), addRequest)
+	result, err := client.Add(ctx, addRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Heartbeat.Name)
 
-	return resourceOpsgenieHeartbeatRead(d, meta)
+	return diag.FromErr(resourceOpsgenieHeartbeatRead(ctx, d, meta))
 }
 
-func resourceOpsgenieHeartbeatRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieHeartbeatRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := heartbeat.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
 	}
 
-	result, err := client.Get(context.Background(), d.Id())
+	result, err := client.Get(ctx, d.Id())
 	if err != nil {
 		return err
 	}
@@ -130,10 +148,10 @@ func resourceOpsgenieHeartbeatRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceOpsgenieHeartbeatUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieHeartbeatUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := heartbeat.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -160,23 +178,23 @@ func resourceOpsgenieHeartbeatUpdate(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	_, err = client.Update(context.Background(), updateRequest)
+	_, err = client.Update(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsgenieHeartbeatDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieHeartbeatDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := heartbeat.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	_, err = client.Delete(context.Background(), d.Id())
+	_, err = client.Delete(ctx, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -210,3 +228,26 @@ func validateOpsgenieHeartbeat(v interface{}, k string) (ws []string, errors []e
 
 	return
 }
+
+// suppressEquivalentHeartbeatInterval suppresses the diff of interval and
+// interval_unit when both pairs describe the same duration, e.g. 60 minutes
+// and 1 hours.
+func suppressEquivalentHeartbeatInterval(k, old, new string, d *schema.ResourceData) bool {
+	oldInterval, newInterval := d.GetChange("interval")
+	oldUnit, newUnit := d.GetChange("interval_unit")
+	if oldUnit.(string) == "" {
+		return false
+	}
+	return heartbeatIntervalMinutes(oldInterval.(int), oldUnit.(string)) == heartbeatIntervalMinutes(newInterval.(int), newUnit.(string))
+}
+
+func heartbeatIntervalMinutes(interval int, unit string) int {
+	switch heartbeat.Unit(unit) {
+	case heartbeat.Hours:
+		return interval * 60
+	case heartbeat.Days:
+		return interval * 24 * 60
+	default:
+		return interval
+	}
+}
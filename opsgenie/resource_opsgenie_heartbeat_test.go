@@ -24,7 +24,7 @@ func init() {
 }
 
 func testSweepHeartbeat(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +39,7 @@ func testSweepHeartbeat(region string) error {
 	}
 
 	for _, u := range resp.Heartbeats {
-		if strings.HasPrefix(u.Name, "genieheartbeat-") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying heartbeat %s", u.Name)
 
 			if _, err := client.Delete(context.Background(), u.Name); err != nil {
@@ -138,3 +138,85 @@ resource "opsgenie_heartbeat" "test" {
 `, randomTeam, randomHeartbeat)
 
 }
+
+func TestSuppressEquivalentHeartbeatInterval(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "heartbeat",
+		Attributes: map[string]string{
+			"id":            "heartbeat",
+			"name":          "heartbeat",
+			"enabled":       "true",
+			"interval":      "60",
+			"interval_unit": "minutes",
+		},
+	}
+
+	cases := []struct {
+		interval int
+		unit     string
+		diff     bool
+	}{
+		{60, "minutes", false},
+		{1, "hours", false},
+		{2, "hours", true},
+		{1440, "minutes", true},
+	}
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":          "heartbeat",
+			"enabled":       true,
+			"interval":      c.interval,
+			"interval_unit": c.unit,
+		})
+		diff, err := resourceOpsgenieHeartbeat().SimpleDiff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatalf("%d %s: %s", c.interval, c.unit, err)
+		}
+		hasDiff := diff != nil && (diff.Attributes["interval"] != nil || diff.Attributes["interval_unit"] != nil)
+		if hasDiff != c.diff {
+			t.Errorf("%d %s: expected diff %t, got %#v", c.interval, c.unit, c.diff, diff)
+		}
+	}
+}
+
+func TestResourceOpsgenieHeartbeatInterval(t *testing.T) {
+	cases := []struct {
+		interval int
+		unit     string
+		expected string
+	}{
+		{0, "minutes", "expected interval to be at least (1), got 0"},
+		{1, "minutes", ""},
+		{43201, "minutes", ""},
+		{721, "hours", ""},
+		{31, "days", ""},
+	}
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":          "heartbeat",
+			"enabled":       true,
+			"interval":      c.interval,
+			"interval_unit": c.unit,
+		})
+		diags := resourceOpsgenieHeartbeat().Validate(config)
+		if c.expected == "" && diags.HasError() {
+			t.Errorf("%d %s: expected no error, got %v", c.interval, c.unit, diags)
+		}
+		if c.expected != "" && (!diags.HasError() || !strings.Contains(diags[0].Summary, c.expected)) {
+			t.Errorf("%d %s: expected %q, got %v", c.interval, c.unit, c.expected, diags)
+		}
+	}
+}
+
+func TestHeartbeatIntervalMinutes(t *testing.T) {
+	cases := map[string]int{
+		"minutes": 90,
+		"hours":   90 * 60,
+		"days":    90 * 24 * 60,
+	}
+	for unit, expected := range cases {
+		if got := heartbeatIntervalMinutes(90, unit); got != expected {
+			t.Errorf("%s: expected %d, got %d", unit, expected, got)
+		}
+	}
+}
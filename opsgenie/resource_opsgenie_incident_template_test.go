@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -23,7 +22,7 @@ func init() {
 }
 
 func testSweepIncidentTemplate(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -37,7 +36,7 @@ func testSweepIncidentTemplate(region string) error {
 	}
 	if result != nil {
 		for _, value := range result.IncidentTemplates["incidentTemplates"] {
-			if strings.HasPrefix(value.Name, "genietest-incident-template-") {
+			if isSweepable(value.Name) {
 				log.Printf("Destroying incident template %s", value.Name)
 				deleteRequest := incident.DeleteIncidentTemplateRequest{IncidentTemplateId: value.IncidentTemplateId}
 				if _, err := client.DeleteIncidentTemplate(context.Background(), &deleteRequest); err != nil {
@@ -82,7 +81,7 @@ func testCheckOpsGenieIncidentTemplateDestroy(s *terraform.State) error {
 			}
 		} else if result != nil {
 			for _, value := range result.IncidentTemplates["incidentTemplates"] {
-				if strings.HasPrefix(value.Name, "genietest-incident-template-") {
+				if isSweepable(value.Name) {
 					return fmt.Errorf("incident template still exists(it shouldn't exist)")
 				}
 			}
@@ -100,7 +99,7 @@ func testCheckOpsGenieIncidentTemplateExists() resource.TestCheckFunc {
 		result, err := client.GetIncidentTemplate(context.Background(), &incident.GetIncidentTemplateRequest{})
 		if err != nil && result != nil {
 			for _, value := range result.IncidentTemplates["incidentTemplates"] {
-				if strings.HasPrefix(value.Name, "genietest-incident-template-") {
+				if isSweepable(value.Name) {
 					log.Printf("Incident template found.")
 					return nil
 				}
@@ -2,10 +2,12 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/integration"
@@ -13,13 +15,20 @@ import (
 
 func resourceOpsgenieIntegrationAction() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieIntegrationActionCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieIntegrationActionRead),
-		Update: resourceOpsgenieIntegrationActionUpdate,
-		Delete: resourceOpsgenieIntegrationActionDelete,
+		CreateContext: resourceOpsgenieIntegrationActionCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieIntegrationActionRead),
+		UpdateContext: resourceOpsgenieIntegrationActionUpdate,
+		DeleteContext: resourceOpsgenieIntegrationActionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffIntegrationActionFilters,
 		Schema: map[string]*schema.Schema{
 			"integration_id": {
 				Type:     schema.TypeString,
@@ -708,10 +717,10 @@ func flattenOpsgenieIntegrationActions(input []integration.IntegrationAction) []
 	return actions
 }
 
-func resourceOpsgenieIntegrationActionCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieIntegrationActionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	integrationId := d.Get("integration_id").(string)
@@ -725,24 +734,24 @@ func resourceOpsgenieIntegrationActionCreate(d *schema.ResourceData, meta interf
 	}
 
 	log.Printf("[INFO] Creating OpsGenie integration actions for '%s'", integrationId)
-	result, err := client.UpdateAllActions(context.Background(), updateRequest)
+	result, err := client.UpdateAllActions(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Parent.Id)
 	d.Set("integration_id", result.Parent.Id)
 
-	return resourceOpsgenieIntegrationActionRead(d, meta)
+	return diag.FromErr(resourceOpsgenieIntegrationActionRead(ctx, d, meta))
 }
 
-func resourceOpsgenieIntegrationActionRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieIntegrationActionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
 	}
 
-	result, err := client.GetActions(context.Background(), &integration.GetIntegrationActionsRequest{
+	result, err := client.GetActions(ctx, &integration.GetIntegrationActionsRequest{
 		BaseRequest: ogClient.BaseRequest{},
 		Id:          d.Id(),
 	})
@@ -760,15 +769,15 @@ func resourceOpsgenieIntegrationActionRead(d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceOpsgenieIntegrationActionUpdate(d *schema.ResourceData, meta interface{}) error {
-	return resourceOpsgenieIntegrationActionCreate(d, meta)
+func resourceOpsgenieIntegrationActionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceOpsgenieIntegrationActionCreate(ctx, d, meta)
 }
 
-func resourceOpsgenieIntegrationActionDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieIntegrationActionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie api integration actions for '%s'", d.Get("integration_id").(string))
 	client, err := integration.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	deleteRequest := &integration.UpdateAllIntegrationActionsRequest{
@@ -780,11 +789,11 @@ func resourceOpsgenieIntegrationActionDelete(d *schema.ResourceData, meta interf
 		Ignore:      []integration.IntegrationAction{},
 	}
 
-	_, err = client.UpdateAllActions(context.Background(), deleteRequest)
+	_, err = client.UpdateAllActions(ctx, deleteRequest)
 	if err != nil {
 		apiError := err.(*ogClient.ApiError)
 		if apiError.StatusCode != 404 {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -24,7 +23,7 @@ func init() {
 }
 
 func testSweepIntegrationAction(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +38,7 @@ func testSweepIntegrationAction(region string) error {
 	}
 
 	for _, u := range resp.Integrations {
-		if strings.HasPrefix(u.Name, "genieintegration-") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying integration actions for id: %s", u.Name)
 
 			deleteRequest := integration.UpdateAllIntegrationActionsRequest{
@@ -2,7 +2,7 @@ package opsgenie
 
 import (
 	"context"
-	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"time"
 
@@ -13,12 +13,18 @@ import (
 
 func resourceOpsgenieMaintenance() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieMaintenanceCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieMaintenanceRead),
-		Update: resourceOpsgenieMaintenanceUpdate,
-		Delete: resourceOpsgenieMaintenanceDelete,
+		CreateContext: resourceOpsgenieMaintenanceCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieMaintenanceRead),
+		UpdateContext: resourceOpsgenieMaintenanceUpdate,
+		DeleteContext: resourceOpsgenieMaintenanceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"description": {
@@ -79,10 +85,10 @@ func resourceOpsgenieMaintenance() *schema.Resource {
 	}
 }
 
-func resourceOpsgenieMaintenanceCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieMaintenanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := maintenance.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	description := d.Get("description").(string)
 
@@ -94,22 +100,22 @@ func resourceOpsgenieMaintenanceCreate(d *schema.ResourceData, meta interface{})
 
 	log.Printf("[INFO] Creating OpsGenie maintenance")
 
-	result, err := client.Create(context.Background(), createRequest)
+	result, err := client.Create(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsgenieMaintenanceRead(d, meta)
+	return diag.FromErr(resourceOpsgenieMaintenanceRead(ctx, d, meta))
 }
 
-func resourceOpsgenieMaintenanceRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieMaintenanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := maintenance.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
 	}
-	listResponse, err := client.List(context.Background(), &maintenance.ListRequest{})
+	listResponse, err := client.List(ctx, &maintenance.ListRequest{})
 	if err != nil {
 		return err
 	}
@@ -138,29 +144,29 @@ func resourceOpsgenieMaintenanceRead(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
-func resourceOpsgenieMaintenanceUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieMaintenanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := maintenance.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	mnt, err := client.Get(context.Background(), &maintenance.GetRequest{
+	mnt, err := client.Get(ctx, &maintenance.GetRequest{
 		Id: d.Id(),
 	})
 	if err != nil {
 		log.Printf("[ERROR] Maintenance could not fetch")
-		return err
+		return diag.FromErr(err)
 
 	}
 	maintenanceTime := expandOpsgenieMaintenanceTime(d)
 	if mnt.Status == "active" {
 
-		_, err := client.ChangeEndDate(context.Background(), &maintenance.ChangeEndDateRequest{
+		_, err := client.ChangeEndDate(ctx, &maintenance.ChangeEndDateRequest{
 			Id:      d.Id(),
 			EndDate: maintenanceTime.EndDate,
 		})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 	} else if mnt.Status == "planned" {
@@ -175,33 +181,33 @@ func resourceOpsgenieMaintenanceUpdate(d *schema.ResourceData, meta interface{})
 
 		log.Printf("[INFO] Updating OpsGenie maintenance")
 
-		_, err = client.Update(context.Background(), updateRequest)
+		_, err = client.Update(ctx, updateRequest)
 		if err != nil {
 			log.Printf("%s", err.Error())
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
 		log.Printf("[ERROR] You cannot edit past maintenance")
-		return errors.New("You cannot edit" + mnt.Status + " maintenances")
+		return diag.Errorf("You cannot edit %s maintenances", mnt.Status)
 
 	}
 
 	return nil
 }
 
-func resourceOpsgenieMaintenanceDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieMaintenanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie escalation ")
 	client, err := maintenance.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &maintenance.DeleteRequest{
 		Id: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
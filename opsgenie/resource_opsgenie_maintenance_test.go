@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +24,7 @@ func init() {
 }
 
 func testSweepMaintenance(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -40,7 +39,7 @@ func testSweepMaintenance(region string) error {
 	}
 
 	for _, u := range resp.Maintenances {
-		if strings.HasPrefix(u.Description, "geniemaintenance-") {
+		if isSweepable(u.Description) {
 			log.Printf("Destroying maintenance %s", u.Description)
 
 			deleteRequest := maintenance.DeleteRequest{
@@ -3,9 +3,12 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
@@ -32,12 +35,12 @@ var (
 
 func resourceOpsGenieNotificationPolicy() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieNotificationPolicyCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieNotificationPolicyRead),
-		Update: resourceOpsGenieNotificationPolicyUpdate,
-		Delete: resourceOpsGenieNotificationPolicyDelete,
+		CreateContext: resourceOpsGenieNotificationPolicyCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieNotificationPolicyRead),
+		UpdateContext: resourceOpsGenieNotificationPolicyUpdate,
+		DeleteContext: resourceOpsGenieNotificationPolicyDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), "/")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("Unexpected format of ID (%q), expected team_id/notification_policy_id", d.Id())
@@ -47,6 +50,16 @@ func resourceOpsGenieNotificationPolicy() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffFilter("filter"),
+			customizeDiffTimeRestriction("time_restriction"),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -291,10 +304,10 @@ func resourceOpsGenieNotificationPolicy() *schema.Resource {
 	}
 }
 
-func resourceOpsGenieNotificationPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieNotificationPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	suppress := d.Get("suppress").(bool)
@@ -317,17 +330,17 @@ func resourceOpsGenieNotificationPolicyCreate(d *schema.ResourceData, meta inter
 	}
 
 	log.Printf("[INFO] Creating Notification Policy '%s'", d.Get("name").(string))
-	result, err := client.CreateNotificationPolicy(context.Background(), createRequest)
+	result, err := client.CreateNotificationPolicy(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsGenieNotificationPolicyRead(d, meta)
+	return diag.FromErr(resourceOpsGenieNotificationPolicyRead(ctx, d, meta))
 }
 
-func resourceOpsGenieNotificationPolicyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieNotificationPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -336,7 +349,7 @@ func resourceOpsGenieNotificationPolicyRead(d *schema.ResourceData, meta interfa
 
 	log.Printf("[INFO] Reading OpsGenie Notification Policy '%s'", name)
 
-	policy, err := client.GetNotificationPolicy(context.Background(), &policy.GetNotificationPolicyRequest{
+	policy, err := client.GetNotificationPolicy(ctx, &policy.GetNotificationPolicyRequest{
 		Id:     d.Id(),
 		TeamId: d.Get("team_id").(string),
 	})
@@ -390,10 +403,10 @@ func resourceOpsGenieNotificationPolicyRead(d *schema.ResourceData, meta interfa
 	return nil
 }
 
-func resourceOpsGenieNotificationPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieNotificationPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	suppress := d.Get("suppress").(bool)
@@ -417,19 +430,19 @@ func resourceOpsGenieNotificationPolicyUpdate(d *schema.ResourceData, meta inter
 	}
 
 	log.Printf("[INFO] Updating Notification Policy '%s'", d.Get("name").(string))
-	_, err = client.UpdateNotificationPolicy(context.Background(), updateRequest)
+	_, err = client.UpdateNotificationPolicy(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsGenieNotificationPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieNotificationPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie Notification Policy '%s'", d.Get("name").(string))
 	client, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &policy.DeletePolicyRequest{
 		Id:     d.Id(),
@@ -437,9 +450,9 @@ func resourceOpsGenieNotificationPolicyDelete(d *schema.ResourceData, meta inter
 		Type:   "notification",
 	}
 
-	_, err = client.DeletePolicy(context.Background(), deleteRequest)
+	_, err = client.DeletePolicy(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
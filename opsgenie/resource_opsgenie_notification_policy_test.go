@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -25,7 +24,7 @@ func init() {
 }
 
 func testSweepNotificationPolicy(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -40,7 +39,7 @@ func testSweepNotificationPolicy(region string) error {
 	}
 
 	for _, u := range resp.Teams {
-		if strings.HasPrefix(u.Name, "genieteam") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying notification policy for team %s", u.Name)
 			client2, err := policy.NewClient(meta.(*OpsgenieClient).client.Config)
 			if err != nil {
@@ -11,7 +11,6 @@ import (
 	"github.com/opsgenie/opsgenie-go-sdk-v2/notification"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
 	"log"
-	"strings"
 	"testing"
 )
 
@@ -24,7 +23,7 @@ func init() {
 }
 
 func testSweepNotificationRule(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -43,7 +42,7 @@ func testSweepNotificationRule(region string) error {
 		return err
 	}
 	for _, u := range respUser.Users {
-		if strings.HasPrefix(u.Username, "genietest-") {
+		if isSweepable(u.Username) {
 			resp, err := client.ListRule(context.Background(), &notification.ListRuleRequest{
 				UserIdentifier: u.Id,
 			})
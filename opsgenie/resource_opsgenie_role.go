@@ -2,8 +2,10 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"log"
+	"time"
 
 	"github.com/opsgenie/opsgenie-go-sdk-v2/custom_user_role"
 
@@ -58,12 +60,18 @@ var validCustomRolesRights = []string{
 
 func resourceOpsGenieCustomUserRole() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieCustomUserRoleCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieCustomUserRoleRead),
-		Update: resourceOpsGenieCustomUserRoleUpdate,
-		Delete: resourceOpsGenieCustomUserRoleDelete,
+		CreateContext: resourceOpsGenieCustomUserRoleCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieCustomUserRoleRead),
+		UpdateContext: resourceOpsGenieCustomUserRoleUpdate,
+		DeleteContext: resourceOpsGenieCustomUserRoleDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"role_name": {
@@ -112,10 +120,10 @@ func flattenSet(input *schema.Set) []string {
 	return output
 }
 
-func resourceOpsGenieCustomUserRoleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieCustomUserRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := custom_user_role.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	UserRoleName := d.Get("role_name").(string)
@@ -124,7 +132,7 @@ func resourceOpsGenieCustomUserRoleCreate(d *schema.ResourceData, meta interface
 	DisallowedRights := flattenSet(d.Get("disallowed_rights").(*schema.Set))
 
 	log.Printf("[INFO] Creating OpsGenie custom user role '%s'", UserRoleName)
-	result, err := client.Create(context.Background(), &custom_user_role.CreateRequest{
+	result, err := client.Create(ctx, &custom_user_role.CreateRequest{
 		Name:             UserRoleName,
 		ExtendedRole:     custom_user_role.ExtendedRole(ExtendedUserRole),
 		GrantedRights:    GrantedRights,
@@ -132,14 +140,14 @@ func resourceOpsGenieCustomUserRoleCreate(d *schema.ResourceData, meta interface
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
-	return resourceOpsGenieCustomUserRoleRead(d, meta)
+	return diag.FromErr(resourceOpsGenieCustomUserRoleRead(ctx, d, meta))
 }
 
-func resourceOpsGenieCustomUserRoleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieCustomUserRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := custom_user_role.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -148,7 +156,7 @@ func resourceOpsGenieCustomUserRoleRead(d *schema.ResourceData, meta interface{}
 
 	log.Printf("[INFO] Reading OpsGenie custom role '%s'", UserRoleName)
 
-	usrRole, err := client.Get(context.Background(), &custom_user_role.GetRequest{
+	usrRole, err := client.Get(ctx, &custom_user_role.GetRequest{
 		Identifier:     UserRoleName,
 		IdentifierType: custom_user_role.Name,
 	})
@@ -164,10 +172,10 @@ func resourceOpsGenieCustomUserRoleRead(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
-func resourceOpsGenieCustomUserRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieCustomUserRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := custom_user_role.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	UserRoleName := d.Get("role_name").(string)
@@ -177,7 +185,7 @@ func resourceOpsGenieCustomUserRoleUpdate(d *schema.ResourceData, meta interface
 
 	log.Printf("[INFO] Updating OpsGenie custom user role '%s'", UserRoleName)
 
-	_, err = client.Update(context.Background(), &custom_user_role.UpdateRequest{
+	_, err = client.Update(ctx, &custom_user_role.UpdateRequest{
 		Identifier:       d.Id(),
 		IdentifierType:   custom_user_role.Id,
 		Name:             UserRoleName,
@@ -187,27 +195,27 @@ func resourceOpsGenieCustomUserRoleUpdate(d *schema.ResourceData, meta interface
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsGenieCustomUserRoleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieCustomUserRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := custom_user_role.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[INFO] Deleting OpsGenie custom user role '%s'", d.Get("role_name").(string))
 
-	_, err = client.Delete(context.Background(), &custom_user_role.DeleteRequest{
+	_, err = client.Delete(ctx, &custom_user_role.DeleteRequest{
 		Identifier:     d.Id(),
 		IdentifierType: custom_user_role.Id,
 	})
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -11,19 +11,21 @@ import (
 	"github.com/opsgenie/opsgenie-go-sdk-v2/custom_user_role"
 	"log"
 	"regexp"
-	"strings"
 	"testing"
 )
 
 func init() {
 	resource.AddTestSweepers("opsgenie_role", &resource.Sweeper{
 		Name: "opsgenie_role",
-		F:    testSweepUserRole,
+		Dependencies: []string{
+			"opsgenie_user",
+		},
+		F: testSweepUserRole,
 	})
 }
 
 func testSweepUserRole(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -38,8 +40,8 @@ func testSweepUserRole(region string) error {
 	}
 
 	for _, u := range resp.CustomUserRoles {
-		if strings.HasPrefix(u.Name, "genietest-") {
-			log.Printf("Destroying user %s", u.Name)
+		if isSweepable(u.Name) {
+			log.Printf("Destroying custom role %s", u.Name)
 
 			deleteRequest := custom_user_role.DeleteRequest{
 				Identifier: u.Id,
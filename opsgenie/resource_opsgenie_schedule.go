@@ -2,7 +2,9 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"time"
 
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
@@ -16,13 +18,20 @@ import (
 
 func resourceOpsgenieSchedule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieScheduleCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieScheduleRead),
-		Update: resourceOpsgenieScheduleUpdate,
-		Delete: resourceOpsgenieScheduleDelete,
+		CreateContext: resourceOpsgenieScheduleCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieScheduleRead),
+		UpdateContext: resourceOpsgenieScheduleUpdate,
+		DeleteContext: resourceOpsgenieScheduleDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceOpsgenieScheduleImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffScheduleRotations,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -35,8 +44,11 @@ func resourceOpsgenieSchedule() *schema.Resource {
 				ValidateFunc: validateOpsgenieScheduleDescription,
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimezone,
+				DiffSuppressFunc: checkTimeZoneDiff,
 			},
 			"enabled": {
 				Type:     schema.TypeBool,
@@ -46,14 +58,48 @@ func resourceOpsgenieSchedule() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"rotation": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        resourceOpsgenieScheduleInlineRotation(),
+				Description: "Rotations of the schedule. Rotations that are not listed are removed, unless no rotation is listed",
+			},
 		},
 	}
 }
 
-func resourceOpsgenieScheduleCreate(d *schema.ResourceData, meta interface{}) error {
+// resourceOpsgenieScheduleInlineRotation is a rotation block of
+// opsgenie_schedule, with the arguments of opsgenie_schedule_rotation.
+func resourceOpsgenieScheduleInlineRotation() *schema.Resource {
+	rotationSchema := resourceOpsgenieScheduleRotation().Schema
+	delete(rotationSchema, "schedule_id")
+	rotationSchema["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	return &schema.Resource{Schema: rotationSchema}
+}
+
+func customizeDiffScheduleRotations(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("rotation") {
+		return nil
+	}
+	for i := range d.Get("rotation").([]interface{}) {
+		path := fmt.Sprintf("rotation.%d", i)
+		if err := validateTimeRestrictionPlan(d, path+".time_restriction"); err != nil {
+			return err
+		}
+		if err := validateScheduleRotationPlan(d, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceOpsgenieScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -72,20 +118,49 @@ func resourceOpsgenieScheduleCreate(d *schema.ResourceData, meta interface{}) er
 			Id: ownerTeam,
 		}
 	}
+	for _, v := range d.Get("rotation").([]interface{}) {
+		rotation, err := expandOpsgenieScheduleRotation(v.(map[string]interface{}))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		createRequest.Rotations = append(createRequest.Rotations, *rotation)
+	}
 
 	log.Printf("[INFO] Creating OpsGenie schedule '%s'", name)
 
-	result, err := client.Create(context.Background(), createRequest)
+	result, err := client.Create(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsgenieScheduleRead(d, meta)
+	return diag.FromErr(resourceOpsgenieScheduleRead(ctx, d, meta))
+}
+
+func resourceOpsgenieScheduleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, ok := importName(d.Id())
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &schedule.GetRequest{
+		IdentifierType:  schedule.Name,
+		IdentifierValue: name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not find schedule %q to import: %s", name, err)
+	}
+
+	d.SetId(result.Schedule.Id)
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceOpsgenieScheduleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -96,7 +171,7 @@ func resourceOpsgenieScheduleRead(d *schema.ResourceData, meta interface{}) erro
 		IdentifierValue: d.Id(),
 	}
 
-	getResponse, err := client.Get(context.Background(), getRequest)
+	getResponse, err := client.Get(ctx, getRequest)
 	if err != nil {
 		return err
 	}
@@ -109,13 +184,109 @@ func resourceOpsgenieScheduleRead(d *schema.ResourceData, meta interface{}) erro
 	d.Set("description", getResponse.Schedule.Description)
 	d.Set("enabled", getResponse.Schedule.Enabled)
 
+	// rotations are only managed inline once a rotation block is used, so
+	// that they can be managed with opsgenie_schedule_rotation instead
+	prior := d.Get("rotation").([]interface{})
+	if len(prior) > 0 {
+		rotations, err := client.ListRotations(ctx, &schedule.ListRotationsRequest{
+			ScheduleIdentifierType:  schedule.Id,
+			ScheduleIdentifierValue: d.Id(),
+		})
+		if err != nil {
+			return err
+		}
+		d.Set("rotation", flattenOpsgenieScheduleInlineRotations(orderScheduleRotations(rotations.Rotations, prior), prior))
+	}
+
 	return nil
 }
 
-func resourceOpsgenieScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+// orderScheduleRotations orders the rotations like the prior rotation blocks,
+// matching them by id, or by name for blocks that were not created yet.
+// Rotations that match no block fill the remaining positions in the order of
+// the API, and rotations created outside of Terraform come last.
+func orderScheduleRotations(rotations []schedule.Rotation, prior []interface{}) []schedule.Rotation {
+	slots := make([]*schedule.Rotation, len(prior))
+	used := make([]bool, len(rotations))
+	for _, field := range []string{"id", "name"} {
+		for i, p := range prior {
+			config, ok := p.(map[string]interface{})
+			if !ok || slots[i] != nil || config[field] == nil || config[field].(string) == "" {
+				continue
+			}
+			for j := range rotations {
+				value := rotations[j].Id
+				if field == "name" {
+					value = rotations[j].Name
+				}
+				if !used[j] && value == config[field].(string) {
+					slots[i] = &rotations[j]
+					used[j] = true
+					break
+				}
+			}
+		}
+	}
+
+	ordered := make([]schedule.Rotation, 0, len(rotations))
+	next := 0
+	for _, slot := range slots {
+		if slot == nil {
+			for next < len(rotations) && used[next] {
+				next++
+			}
+			if next == len(rotations) {
+				continue
+			}
+			slot = &rotations[next]
+			used[next] = true
+		}
+		ordered = append(ordered, *slot)
+	}
+	for j := range rotations {
+		if !used[j] {
+			ordered = append(ordered, rotations[j])
+		}
+	}
+	return ordered
+}
+
+func flattenOpsgenieScheduleInlineRotations(rotations []schedule.Rotation, prior []interface{}) []map[string]interface{} {
+	output := make([]map[string]interface{}, 0, len(rotations))
+	for i, rotation := range rotations {
+		var priorParticipants []interface{}
+		if i < len(prior) {
+			if config, ok := prior[i].(map[string]interface{}); ok {
+				priorParticipants, _ = config["participant"].([]interface{})
+			}
+		}
+
+		out := map[string]interface{}{
+			"id":          rotation.Id,
+			"name":        rotation.Name,
+			"type":        string(rotation.Type),
+			"length":      int(rotation.Length),
+			"participant": normalizeResponderIdentifiers(flattenOpsgenieScheduleRotationParticipant(rotation.Participants), priorParticipants),
+		}
+		if rotation.StartDate != nil {
+			out["start_date"] = rotation.StartDate.UTC().Format("2006-01-02T15:04:05Z")
+			out["handoff_time"], out["handoff_day"] = scheduleRotationHandoff(*rotation.StartDate, string(rotation.Type))
+		}
+		if rotation.EndDate != nil {
+			out["end_date"] = rotation.EndDate.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		if rotation.TimeRestriction != nil {
+			out["time_restriction"] = flattenOpsgenieScheduleRotationTimeRestriction(rotation.TimeRestriction)
+		}
+		output = append(output, out)
+	}
+	return output
+}
+
+func resourceOpsgenieScheduleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -141,28 +312,199 @@ func resourceOpsgenieScheduleUpdate(d *schema.ResourceData, meta interface{}) er
 	}
 	log.Printf("[INFO] Updating OpsGenie schedule '%s'", name)
 
-	_, err = client.Update(context.Background(), updateRequest)
+	_, err = client.Update(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return nil
+	if d.HasChange("rotation") {
+		if err := updateOpsgenieScheduleInlineRotations(ctx, client, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diag.FromErr(resourceOpsgenieScheduleRead(ctx, d, meta))
+}
+
+// updateOpsgenieScheduleInlineRotations deletes the rotations that are no
+// longer listed, updates the changed ones and creates the new ones. New
+// blocks adopt the existing rotation of the same name that is not managed by
+// another block, e.g. after the schedule was imported, instead of creating a
+// second one next to it.
+func updateOpsgenieScheduleInlineRotations(ctx context.Context, client *schedule.Client, d *schema.ResourceData) error {
+	old, new := d.GetChange("rotation")
+	priorBlocks := old.([]interface{})
+	rotations := new.([]interface{})
+	ids := matchScheduleRotationBlocks(priorBlocks, rotations)
+
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			listed[id] = true
+		}
+	}
+	managed := make(map[string]bool, len(listed))
+	for id := range listed {
+		managed[id] = true
+	}
+	for _, v := range priorBlocks {
+		id := v.(map[string]interface{})["id"].(string)
+		if id == "" {
+			continue
+		}
+		managed[id] = true
+		if listed[id] {
+			continue
+		}
+		log.Printf("[INFO] Deleting OpsGenie rotation '%s' of schedule '%s'", id, d.Id())
+		_, err := client.DeleteRotation(ctx, &schedule.DeleteRotationRequest{
+			ScheduleIdentifierType:  schedule.Id,
+			ScheduleIdentifierValue: d.Id(),
+			RotationId:              id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	adopting := false
+	for _, id := range ids {
+		if id == "" {
+			adopting = true
+			break
+		}
+	}
+	var unmanaged []schedule.Rotation
+	if adopting {
+		existing, err := client.ListRotations(ctx, &schedule.ListRotationsRequest{
+			ScheduleIdentifierType:  schedule.Id,
+			ScheduleIdentifierValue: d.Id(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, rotation := range existing.Rotations {
+			if !managed[rotation.Id] {
+				unmanaged = append(unmanaged, rotation)
+			}
+		}
+	}
+
+	for i, v := range rotations {
+		config := v.(map[string]interface{})
+		id := ids[i]
+		// a block that kept both its rotation and its position is unchanged
+		// unless its arguments changed
+		if id != "" && i < len(priorBlocks) && priorBlocks[i].(map[string]interface{})["id"] == id && !d.HasChange(fmt.Sprintf("rotation.%d", i)) {
+			continue
+		}
+		rotation, err := expandOpsgenieScheduleRotation(config)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			for j := range unmanaged {
+				if unmanaged[j].Id != "" && unmanaged[j].Name == rotation.Name {
+					id = unmanaged[j].Id
+					unmanaged[j].Id = ""
+					log.Printf("[INFO] Adopting existing OpsGenie rotation '%s' of schedule '%s'", id, d.Id())
+					break
+				}
+			}
+		}
+
+		if id == "" {
+			log.Printf("[INFO] Creating OpsGenie rotation '%s' of schedule '%s'", rotation.Name, d.Id())
+			var result *schedule.CreateRotationResult
+			result, err = client.CreateRotation(ctx, &schedule.CreateRotationRequest{
+				ScheduleIdentifierType:  schedule.Id,
+				ScheduleIdentifierValue: d.Id(),
+				Rotation:                rotation,
+			})
+			if err == nil {
+				id = result.Id
+			}
+		} else {
+			log.Printf("[INFO] Updating OpsGenie rotation '%s' of schedule '%s'", id, d.Id())
+			_, err = client.UpdateRotation(ctx, &schedule.UpdateRotationRequest{
+				ScheduleIdentifierType:  schedule.Id,
+				ScheduleIdentifierValue: d.Id(),
+				RotationId:              id,
+				Rotation:                rotation,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		config["id"] = id
+	}
+
+	// the blocks keep the ids of the positions they moved to until they are
+	// read, so store the rotations they were matched with
+	return d.Set("rotation", rotations)
 }
 
-func resourceOpsgenieScheduleDelete(d *schema.ResourceData, meta interface{}) error {
+// matchScheduleRotationBlocks returns the id of the rotation of each of the
+// rotation blocks. Blocks are matched to the prior blocks by name first, so
+// that adding or removing a block does not move the rotations of the blocks
+// after it, and then by the id they got from their position, e.g. when a
+// block was renamed. Blocks without a match get no id.
+func matchScheduleRotationBlocks(prior []interface{}, rotations []interface{}) []string {
+	ids := make([]string, len(rotations))
+	claimed := make(map[string]bool, len(prior))
+	for i, v := range rotations {
+		name := v.(map[string]interface{})["name"].(string)
+		for _, p := range prior {
+			priorBlock := p.(map[string]interface{})
+			id := priorBlock["id"].(string)
+			if id != "" && !claimed[id] && priorBlock["name"].(string) == name {
+				ids[i] = id
+				claimed[id] = true
+				break
+			}
+		}
+	}
+
+	// ids of prior blocks whose name is still listed are not reused by
+	// position, even if that block was matched to another rotation
+	names := make(map[string]bool, len(rotations))
+	for _, v := range rotations {
+		names[v.(map[string]interface{})["name"].(string)] = true
+	}
+	for i, v := range rotations {
+		if ids[i] != "" {
+			continue
+		}
+		id := v.(map[string]interface{})["id"].(string)
+		if id == "" || claimed[id] {
+			continue
+		}
+		for _, p := range prior {
+			priorBlock := p.(map[string]interface{})
+			if priorBlock["id"].(string) == id && !names[priorBlock["name"].(string)] {
+				ids[i] = id
+				claimed[id] = true
+				break
+			}
+		}
+	}
+	return ids
+}
+
+func resourceOpsgenieScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie schedule '%s'", d.Get("name").(string))
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &schedule.DeleteRequest{
 		IdentifierType:  schedule.Id,
 		IdentifierValue: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
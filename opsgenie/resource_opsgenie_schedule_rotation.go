@@ -3,6 +3,7 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"strings"
 	"time"
@@ -10,17 +11,18 @@ import (
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceOpsgenieScheduleRotation() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsgenieScheduleRotationCreate,
-		Read:   handleNonExistentResource(resourceOpsgenieScheduleRotationRead),
-		Update: resourceOpsgenieScheduleRotationUpdate,
-		Delete: resourceOpsgenieScheduleRotationDelete,
+		CreateContext: resourceOpsgenieScheduleRotationCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsgenieScheduleRotationRead),
+		UpdateContext: resourceOpsgenieScheduleRotationUpdate,
+		DeleteContext: resourceOpsgenieScheduleRotationDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), "/")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("Unexpected format of ID (%q), expected schedule_id/schedule_rotation_id", d.Id())
@@ -32,6 +34,16 @@ func resourceOpsgenieScheduleRotation() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffTimeRestriction("time_restriction"),
+			customizeDiffScheduleRotation,
+		),
 		Schema: map[string]*schema.Schema{
 			"schedule_id": {
 				Type:     schema.TypeString,
@@ -60,6 +72,16 @@ func resourceOpsgenieScheduleRotation() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"handoff_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time of day the shifts change, as HH:MM in UTC",
+			},
+			"handoff_day": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Day of the week the shifts change, for weekly rotations",
+			},
 			"participant": {
 				Type:     schema.TypeList,
 				Required: true,
@@ -74,6 +96,16 @@ func resourceOpsgenieScheduleRotation() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the team or escalation, instead of id",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username of the user, instead of id",
+						},
 					},
 				},
 			},
@@ -160,10 +192,10 @@ func resourceOpsgenieScheduleRotation() *schema.Resource {
 	}
 }
 
-func resourceOpsgenieScheduleRotationCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieScheduleRotationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	scheduleIdentiferValue := d.Get("schedule_id").(string)
 
@@ -177,7 +209,7 @@ func resourceOpsgenieScheduleRotationCreate(d *schema.ResourceData, meta interfa
 	layoutStr := "2006-01-02T15:04:05Z"
 	startDate, err := time.Parse(layoutStr, start_date)
 	if err != nil {
-		return fmt.Errorf("Cannot parse date-time")
+		return diag.Errorf("Cannot parse date-time")
 	}
 
 	createRequest := &schedule.CreateRotationRequest{
@@ -210,17 +242,17 @@ func resourceOpsgenieScheduleRotationCreate(d *schema.ResourceData, meta interfa
 
 	log.Printf("[INFO] Creating OpsGenie rotation '%s'", name)
 
-	result, err := client.CreateRotation(context.Background(), createRequest)
+	result, err := client.CreateRotation(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsgenieScheduleRotationRead(d, meta)
+	return diag.FromErr(resourceOpsgenieScheduleRotationRead(ctx, d, meta))
 }
 
-func resourceOpsgenieScheduleRotationRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieScheduleRotationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -233,7 +265,7 @@ func resourceOpsgenieScheduleRotationRead(d *schema.ResourceData, meta interface
 		ScheduleIdentifierValue: scheduleIdentiferValue,
 		RotationId:              d.Id(),
 	}
-	getResponse, err := client.GetRotation(context.Background(), getRequest)
+	getResponse, err := client.GetRotation(ctx, getRequest)
 	if err != nil {
 		return err
 	}
@@ -242,11 +274,14 @@ func resourceOpsgenieScheduleRotationRead(d *schema.ResourceData, meta interface
 	d.Set("name", getResponse.Rotation.Name)
 	d.Set("length", getResponse.Length)
 	d.Set("type", getResponse.Type)
-	d.Set("participant", flattenOpsgenieScheduleRotationParticipant(getResponse.Participants))
+	d.Set("participant", normalizeResponderIdentifiers(flattenOpsgenieScheduleRotationParticipant(getResponse.Participants), d.Get("participant").([]interface{})))
 	if getResponse.TimeRestriction != nil {
 		d.Set("time_restriction", flattenOpsgenieScheduleRotationTimeRestriction(getResponse.TimeRestriction))
 	}
 	d.Set("start_date", startDate)
+	handoffTime, handoffDay := scheduleRotationHandoff(*getResponse.StartDate, string(getResponse.Type))
+	d.Set("handoff_time", handoffTime)
+	d.Set("handoff_day", handoffDay)
 	if getResponse.EndDate != nil {
 		endDate := getResponse.EndDate.Format("2006-01-02T15:04:05Z")
 		d.Set("end_date", endDate)
@@ -255,11 +290,127 @@ func resourceOpsgenieScheduleRotationRead(d *schema.ResourceData, meta interface
 	return nil
 }
 
+func customizeDiffScheduleRotation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateScheduleRotationPlan(d, ""); err != nil {
+		return err
+	}
+
+	if !d.NewValueKnown("start_date") || !d.NewValueKnown("type") {
+		d.SetNewComputed("handoff_time")
+		d.SetNewComputed("handoff_day")
+		return nil
+	}
+	startDate, err := time.Parse(time.RFC3339, d.Get("start_date").(string))
+	if err != nil {
+		return nil
+	}
+	handoffTime, handoffDay := scheduleRotationHandoff(startDate, d.Get("type").(string))
+	if err := d.SetNew("handoff_time", handoffTime); err != nil {
+		return err
+	}
+	return d.SetNew("handoff_day", handoffDay)
+}
+
+// scheduleRotationHandoff returns the time of day and, for weekly rotations,
+// the day of the week that the shifts of a rotation starting at startDate
+// change. Hourly rotations hand off every length hours from that time.
+func scheduleRotationHandoff(startDate time.Time, rotationType string) (string, string) {
+	startDate = startDate.UTC()
+	handoffDay := ""
+	if strings.ToLower(rotationType) == string(og.Weekly) {
+		handoffDay = strings.ToLower(startDate.Weekday().String())
+	}
+	return startDate.Format("15:04"), handoffDay
+}
+
+// validateScheduleRotationPlan validates the rotation at path, or at the top
+// level if path is empty, during plan. It rejects end dates that are not
+// after the start date, hourly rotations that do not start on the hour or
+// half hour, rotations without participants to notify, and time
+// restrictions that never let the rotation be on call.
+func validateScheduleRotationPlan(d planValues, path string) error {
+	key := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+
+	if d.NewValueKnown(key("start_date")) {
+		startDate, err := time.Parse(time.RFC3339, d.Get(key("start_date")).(string))
+		if err != nil {
+			return nil
+		}
+
+		if d.NewValueKnown(key("type")) && strings.ToLower(d.Get(key("type")).(string)) == string(og.Hourly) && (startDate.Minute()%30 != 0 || startDate.Second() != 0) {
+			return fmt.Errorf("%s: hourly rotations must start on the hour or half hour, got %s", key("start_date"), startDate.Format(time.RFC3339))
+		}
+
+		if d.NewValueKnown(key("end_date")) {
+			if v := d.Get(key("end_date")).(string); v != "" {
+				endDate, err := time.Parse(time.RFC3339, v)
+				if err == nil && !endDate.After(startDate) {
+					return fmt.Errorf("%s: must be after start_date %s, got %s", key("end_date"), startDate.Format(time.RFC3339), v)
+				}
+			}
+		}
+	}
+
+	if d.NewValueKnown(key("participant")) {
+		participants := d.Get(key("participant")).([]interface{})
+		if len(participants) == 0 {
+			return fmt.Errorf("%s: at least one participant is required", key("participant"))
+		}
+		onlyNone := true
+		for i := range participants {
+			typeKey := fmt.Sprintf("%s.%d.type", key("participant"), i)
+			if !d.NewValueKnown(typeKey) || strings.ToLower(d.Get(typeKey).(string)) != string(og.None) {
+				onlyNone = false
+				break
+			}
+		}
+		if onlyNone {
+			return fmt.Errorf("%s: all participants have type none, so the rotation never notifies anyone", key("participant"))
+		}
+	}
+
+	if d.NewValueKnown(key("time_restriction")) {
+		for i := range d.Get(key("time_restriction")).([]interface{}) {
+			restrictionPath := fmt.Sprintf("%s.%d", key("time_restriction"), i)
+			if !valuesKnown(d, restrictionPath, "type", "restriction", "restrictions") {
+				continue
+			}
+			timeRestriction := d.Get(restrictionPath).(map[string]interface{})
+			if !timeRestrictionHasRestrictions(timeRestriction) {
+				return fmt.Errorf("%s: no restriction is set, so the rotation is never on call", restrictionPath)
+			}
+		}
+	}
+
+	return nil
+}
+
+// timeRestrictionHasRestrictions reports whether the time restriction has a
+// restriction of its type. og.ValidateRestrictions rejects time restrictions
+// without one, which would leave the rotation without any time on call.
+func timeRestrictionHasRestrictions(timeRestriction map[string]interface{}) bool {
+	switch og.RestrictionType(timeRestriction["type"].(string)) {
+	case og.TimeOfDay:
+		return len(timeRestriction["restriction"].([]interface{})) > 0
+	case og.WeekdayAndTimeOfDay:
+		return len(timeRestriction["restrictions"].([]interface{})) > 0
+	default:
+		return true
+	}
+}
+
 func flattenOpsgenieScheduleRotationParticipant(input []og.Participant) []map[string]interface{} {
 	participants := make([]map[string]interface{}, 0, len(input))
 	for _, part := range input {
 		outputMember := make(map[string]interface{})
 		outputMember["id"] = part.Id
+		outputMember["name"] = part.Name
+		outputMember["username"] = part.Username
 		outputMember["type"] = part.Type
 		participants = append(participants, outputMember)
 	}
@@ -310,10 +461,10 @@ func flattenOpsgenieScheduleRotationTimeRestriction(input *og.TimeRestriction) [
 	return output
 }
 
-func resourceOpsgenieScheduleRotationUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieScheduleRotationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	scheduleIdentiferValue := d.Get("schedule_id").(string)
 
@@ -357,19 +508,19 @@ func resourceOpsgenieScheduleRotationUpdate(d *schema.ResourceData, meta interfa
 	}
 	log.Printf("[INFO] Updating OpsGenie schedule rotation '%s'", name)
 
-	_, err = client.UpdateRotation(context.Background(), updateRequest)
+	_, err = client.UpdateRotation(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsgenieScheduleRotationDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsgenieScheduleRotationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie schedule rotation '%s'", d.Get("name").(string))
 	client, err := schedule.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	scheduleIdentiferValue := d.Get("schedule_id").(string)
 
@@ -379,14 +530,43 @@ func resourceOpsgenieScheduleRotationDelete(d *schema.ResourceData, meta interfa
 		RotationId:              d.Id(),
 	}
 
-	_, err = client.DeleteRotation(context.Background(), deleteRequest)
+	_, err = client.DeleteRotation(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
+// expandOpsgenieScheduleRotation builds a rotation from a rotation block of
+// opsgenie_schedule.
+func expandOpsgenieScheduleRotation(config map[string]interface{}) (*og.Rotation, error) {
+	layoutStr := "2006-01-02T15:04:05Z"
+	startDate, err := time.Parse(layoutStr, config["start_date"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse start_date of rotation %q: %s", config["name"], err)
+	}
+
+	rotation := &og.Rotation{
+		Name:         config["name"].(string),
+		StartDate:    &startDate,
+		Length:       uint32(config["length"].(int)),
+		Type:         og.RotationType(config["type"].(string)),
+		Participants: expandOpsgenieScheduleParticipants(config["participant"].([]interface{})),
+	}
+	if v := config["end_date"].(string); v != "" {
+		endDate, err := time.Parse(layoutStr, v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse end_date of rotation %q: %s", config["name"], err)
+		}
+		rotation.EndDate = &endDate
+	}
+	if timeRestriction := config["time_restriction"].([]interface{}); len(timeRestriction) > 0 {
+		rotation.TimeRestriction = expandTimeRestrictions(timeRestriction)
+	}
+	return rotation, nil
+}
+
 func expandOpsgenieScheduleParticipants(input []interface{}) []og.Participant {
 	participants := make([]og.Participant, 0, len(input))
 
@@ -401,8 +581,10 @@ func expandOpsgenieScheduleParticipants(input []interface{}) []og.Participant {
 		Id := config["id"].(string)
 
 		participant := og.Participant{
-			Type: og.ParticipantType(participantType),
-			Id:   Id,
+			Type:     og.ParticipantType(participantType),
+			Id:       Id,
+			Name:     config["name"].(string),
+			Username: config["username"].(string),
 		}
 
 		participants = append(participants, participant)
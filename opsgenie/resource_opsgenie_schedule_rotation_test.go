@@ -5,14 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
+	"time"
 
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -25,7 +26,7 @@ func init() {
 }
 
 func testSweepScheduleRotations(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -40,26 +41,25 @@ func testSweepScheduleRotations(region string) error {
 		return err
 	}
 	for _, u := range scheduleResp.Schedule {
-		if strings.HasPrefix(u.Name, "genieschedule-") {
-
+		if isSweepable(u.Name) {
 			resp, err := client.ListRotations(context.Background(), &schedule.ListRotationsRequest{
-				ScheduleIdentifierType:  schedule.Name,
-				ScheduleIdentifierValue: "acceptance-test",
+				ScheduleIdentifierType:  schedule.Id,
+				ScheduleIdentifierValue: u.Id,
 			})
 			if err != nil {
 				return err
 			}
-			for _, u := range resp.Rotations {
-				if strings.HasPrefix(u.Name, "genierotation-") {
-					log.Printf("Destroying schedule rotation %s", u.Name)
+			for _, r := range resp.Rotations {
+				log.Printf("Destroying schedule rotation %s of schedule %s", r.Name, u.Name)
 
-					deleteRequest := schedule.DeleteRotationRequest{
-						RotationId: u.Id,
-					}
+				deleteRequest := schedule.DeleteRotationRequest{
+					ScheduleIdentifierType:  schedule.Id,
+					ScheduleIdentifierValue: u.Id,
+					RotationId:              r.Id,
+				}
 
-					if _, err := client.DeleteRotation(context.Background(), &deleteRequest); err != nil {
-						return err
-					}
+				if _, err := client.DeleteRotation(context.Background(), &deleteRequest); err != nil {
+					return err
 				}
 			}
 		}
@@ -330,3 +330,130 @@ resource "opsgenie_schedule_rotation" "esc" {
 }
 `, randomName, randomTeam, randomSchedule, randomRotation, randomRotation2, randomRotation2, randomRotation2, randomRotation2)
 }
+
+func TestValidateScheduleRotationPlan_unknownRestrictionFields(t *testing.T) {
+	restrictions := []map[string]interface{}{
+		{"type": unknown},
+		{"type": "time-of-day", "restriction": unknown},
+		{"type": "weekday-and-time-of-day", "restrictions": unknown},
+	}
+	for _, restriction := range restrictions {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"schedule_id":      "schedule",
+			"name":             "rotation",
+			"start_date":       "2021-01-04T09:00:00Z",
+			"type":             "daily",
+			"participant":      []interface{}{map[string]interface{}{"type": "user", "username": "jane@example.com"}},
+			"time_restriction": []interface{}{restriction},
+		})
+		if _, err := resourceOpsgenieScheduleRotation().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil); err != nil {
+			t.Errorf("Expected %v to be valid until its values are known, got %s", restriction, err)
+		}
+	}
+}
+
+func TestValidateScheduleRotationPlan(t *testing.T) {
+	user := map[string]interface{}{"type": "user", "username": "jane@example.com"}
+	none := map[string]interface{}{"type": "none"}
+	restriction := func(startHour, endHour int) map[string]interface{} {
+		return map[string]interface{}{"type": "time-of-day", "restriction": []interface{}{
+			map[string]interface{}{"start_hour": startHour, "start_min": 0, "end_hour": endHour, "end_min": 0},
+		}}
+	}
+	restrictions := func(startDay, endDay string) map[string]interface{} {
+		return map[string]interface{}{"type": "weekday-and-time-of-day", "restrictions": []interface{}{
+			map[string]interface{}{"start_day": startDay, "end_day": endDay, "start_hour": 8, "start_min": 0, "end_hour": 8, "end_min": 0},
+		}}
+	}
+
+	cases := []struct {
+		name     string
+		rotation map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "valid",
+			rotation: map[string]interface{}{"type": "weekly", "end_date": "2021-02-01T09:00:00Z", "participant": []interface{}{user, none}, "time_restriction": []interface{}{restriction(8, 18)}},
+		},
+		{
+			name:     "overnight restriction",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{restriction(18, 8)}},
+		},
+		{
+			name:     "week long restriction",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{restrictions("monday", "sunday")}},
+		},
+		{
+			name:     "end before start",
+			rotation: map[string]interface{}{"type": "weekly", "end_date": "2020-12-01T09:00:00Z", "participant": []interface{}{user}},
+			expected: "end_date: must be after start_date 2021-01-04T09:00:00Z, got 2020-12-01T09:00:00Z",
+		},
+		{
+			name:     "end at start",
+			rotation: map[string]interface{}{"type": "weekly", "end_date": "2021-01-04T09:00:00Z", "participant": []interface{}{user}},
+			expected: "end_date: must be after start_date 2021-01-04T09:00:00Z, got 2021-01-04T09:00:00Z",
+		},
+		{
+			name:     "hourly with seconds",
+			rotation: map[string]interface{}{"type": "hourly", "start_date": "2021-01-04T09:00:15Z", "participant": []interface{}{user}},
+			expected: "start_date: hourly rotations must start on the hour or half hour, got 2021-01-04T09:00:15Z",
+		},
+		{
+			name:     "only none",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{none, none}},
+			expected: "participant: all participants have type none, so the rotation never notifies anyone",
+		},
+		{
+			name:     "time of day ending when it starts",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{restriction(8, 8)}},
+		},
+		{
+			name:     "weekday and time of day ending when it starts",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{restrictions("monday", "monday")}},
+		},
+		{
+			name:     "time of day without restriction",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{map[string]interface{}{"type": "time-of-day"}}},
+			expected: "time_restriction.0: no restriction is set, so the rotation is never on call",
+		},
+		{
+			name:     "weekday and time of day without restrictions",
+			rotation: map[string]interface{}{"type": "daily", "participant": []interface{}{user}, "time_restriction": []interface{}{map[string]interface{}{"type": "weekday-and-time-of-day"}}},
+			expected: "time_restriction.0: no restriction is set, so the rotation is never on call",
+		},
+	}
+
+	for _, c := range cases {
+		raw := map[string]interface{}{
+			"schedule_id": "schedule",
+			"start_date":  "2021-01-04T09:00:00Z",
+		}
+		for k, v := range c.rotation {
+			raw[k] = v
+		}
+		d := schema.TestResourceDataRaw(t, resourceOpsgenieScheduleRotation().Schema, raw)
+		err := validateScheduleRotationPlan(knownPlanValues{d}, "")
+		if c.expected == "" && err != nil {
+			t.Errorf("%s: expected no error, got %s", c.name, err)
+		}
+		if c.expected != "" && (err == nil || err.Error() != c.expected) {
+			t.Errorf("%s: expected %q, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestScheduleRotationHandoff(t *testing.T) {
+	startDate := time.Date(2021, 1, 4, 9, 30, 0, 0, time.UTC)
+	cases := map[string][2]string{
+		"weekly": {"09:30", "monday"},
+		"Weekly": {"09:30", "monday"},
+		"daily":  {"09:30", ""},
+		"hourly": {"09:30", ""},
+	}
+	for rotationType, expected := range cases {
+		handoffTime, handoffDay := scheduleRotationHandoff(startDate, rotationType)
+		if handoffTime != expected[0] || handoffDay != expected[1] {
+			t.Errorf("%s: expected %v, got %s %s", rotationType, expected, handoffTime, handoffDay)
+		}
+	}
+}
@@ -5,26 +5,33 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
+	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
 )
 
 func init() {
 	resource.AddTestSweepers("opsgenie_schedule", &resource.Sweeper{
 		Name: "opsgenie_schedule",
-		F:    testSweepSchedule,
+		Dependencies: []string{
+			"opsgenie_schedule_rotation",
+			"opsgenie_escalation",
+			"opsgenie_team_routing_rule",
+		},
+		F: testSweepSchedule,
 	})
 
 }
 
 func testSweepSchedule(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -40,7 +47,7 @@ func testSweepSchedule(region string) error {
 	}
 
 	for _, u := range resp.Schedule {
-		if strings.HasPrefix(u.Name, "genieschedule-") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying schedule %s", u.Name)
 
 			deleteRequest := schedule.DeleteRequest{
@@ -71,6 +78,12 @@ func TestAccOpsGenieSchedule_basic(t *testing.T) {
 					testCheckOpsGenieScheduleExists("opsgenie_schedule.test"),
 				),
 			},
+			{
+				ResourceName:      "opsgenie_schedule.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("name:genieschedule-%s", rs),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -93,6 +106,42 @@ func TestAccOpsGenieSchedule_complete(t *testing.T) {
 	})
 }
 
+func TestAccOpsGenieSchedule_rotations(t *testing.T) {
+	rs := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testCheckOpsGenieScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOpsGenieSchedule_rotations(rs, true),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckOpsGenieScheduleExists("opsgenie_schedule.test"),
+					resource.TestCheckResourceAttr("opsgenie_schedule.test", "rotation.#", "2"),
+					resource.TestCheckResourceAttr("opsgenie_schedule.test", "rotation.0.name", "weekdays"),
+					resource.TestCheckResourceAttr("opsgenie_schedule.test", "rotation.0.handoff_day", "monday"),
+					resource.TestCheckResourceAttrSet("opsgenie_schedule.test", "rotation.1.id"),
+				),
+			},
+			{
+				Config: testAccOpsGenieSchedule_rotations(rs, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("opsgenie_schedule.test", "rotation.#", "1"),
+					resource.TestCheckResourceAttr("opsgenie_schedule.test", "rotation.0.name", "weekdays"),
+				),
+			},
+			{
+				// rotations are not read on import, see
+				// TestResourceOpsgenieScheduleUpdate_adoptsRotations
+				ResourceName:            "opsgenie_schedule.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rotation"},
+			},
+		},
+	})
+}
+
 func testCheckOpsGenieScheduleDestroy(s *terraform.State) error {
 	client, err := schedule.NewClient(testAccProvider.Meta().(*OpsgenieClient).client.Config)
 	if err != nil {
@@ -174,3 +223,305 @@ resource "opsgenie_schedule" "test" {
 
 `, rString)
 }
+
+func testAccOpsGenieSchedule_rotations(rString string, weekend bool) string {
+	weekendRotation := ""
+	if weekend {
+		weekendRotation = `
+  rotation {
+    name       = "weekend"
+    start_date = "2021-01-02T09:00:00Z"
+    type       = "daily"
+    participant {
+      type = "none"
+    }
+    participant {
+      type     = "user"
+      username = "${opsgenie_user.test.username}"
+    }
+  }`
+	}
+
+	return fmt.Sprintf(`
+resource "opsgenie_user" "test" {
+  username  = "genietest-%s@opsgenie.com"
+  full_name = "genietest-%s"
+  role      = "User"
+}
+resource "opsgenie_schedule" "test" {
+  name     = "genieschedule-%s"
+  timezone = "Europe/Rome"
+  enabled  = false
+
+  rotation {
+    name       = "weekdays"
+    start_date = "2021-01-04T09:00:00Z"
+    type       = "weekly"
+    participant {
+      type     = "user"
+      username = "${opsgenie_user.test.username}"
+    }
+  }%s
+}
+`, rString, rString, rString, weekendRotation)
+}
+
+func TestOrderScheduleRotations(t *testing.T) {
+	rotations := []schedule.Rotation{
+		{Id: "1", Name: "weekend"},
+		{Id: "2", Name: "weekdays"},
+		{Id: "3", Name: "holidays"},
+		{Id: "4", Name: "manual"},
+	}
+
+	cases := []struct {
+		name     string
+		prior    []interface{}
+		expected []string
+	}{
+		{
+			name:     "by id",
+			prior:    []interface{}{map[string]interface{}{"id": "2"}, map[string]interface{}{"id": "1"}},
+			expected: []string{"2", "1", "3", "4"},
+		},
+		{
+			name:     "by name before creation",
+			prior:    []interface{}{map[string]interface{}{"id": "", "name": "holidays"}, map[string]interface{}{"id": "", "name": "weekdays"}},
+			expected: []string{"3", "2", "1", "4"},
+		},
+		{
+			name:     "unnamed fill remaining positions",
+			prior:    []interface{}{map[string]interface{}{"id": "", "name": ""}, map[string]interface{}{"id": "", "name": "weekend"}, map[string]interface{}{"id": "", "name": ""}},
+			expected: []string{"2", "1", "3", "4"},
+		},
+		{
+			name:     "deleted outside of terraform",
+			prior:    []interface{}{map[string]interface{}{"id": "9"}, map[string]interface{}{"id": "4"}},
+			expected: []string{"1", "4", "2", "3"},
+		},
+	}
+
+	for _, c := range cases {
+		ordered := orderScheduleRotations(rotations, c.prior)
+		ids := make([]string, 0, len(ordered))
+		for _, r := range ordered {
+			ids = append(ids, r.Id)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, ids)
+		}
+	}
+}
+
+func TestResourceOpsgenieScheduleUpdate_adoptsRotations(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := schedule.NewClient(meta.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabled := false
+	created, err := client.Create(ctx, &schedule.CreateRequest{Name: "platform", Timezone: "Europe/Rome", Enabled: &enabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	startDate := time.Date(2021, 1, 4, 9, 0, 0, 0, time.UTC)
+	existing := &og.Rotation{Name: "weekdays", StartDate: &startDate, Type: og.Weekly, Participants: []og.Participant{{Type: og.User, Username: "jane@example.com"}}}
+	rotation, err := client.CreateRotation(ctx, &schedule.CreateRotationRequest{
+		ScheduleIdentifierType:  schedule.Id,
+		ScheduleIdentifierValue: created.Id,
+		Rotation:                existing,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the state of the schedule after it was imported, without its rotations
+	r := resourceOpsgenieSchedule()
+	imported := r.Data(&terraform.InstanceState{ID: created.Id})
+	if err := resourceOpsgenieScheduleRead(ctx, imported, meta); err != nil {
+		t.Fatal(err)
+	}
+	state := imported.State()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "platform",
+		"timezone": "Europe/Rome",
+		"enabled":  false,
+		"rotation": []interface{}{
+			map[string]interface{}{
+				"name":        "weekdays",
+				"start_date":  "2021-01-04T09:00:00Z",
+				"type":        "weekly",
+				"participant": []interface{}{map[string]interface{}{"type": "user", "username": "jane@example.com"}},
+			},
+			map[string]interface{}{
+				"name":        "weekend",
+				"start_date":  "2021-01-02T09:00:00Z",
+				"type":        "daily",
+				"participant": []interface{}{map[string]interface{}{"type": "user", "username": "jane@example.com"}},
+			},
+		},
+	})
+	diff, err := r.SimpleDiff(ctx, state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	rotations, err := client.ListRotations(ctx, &schedule.ListRotationsRequest{
+		ScheduleIdentifierType:  schedule.Id,
+		ScheduleIdentifierValue: created.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations.Rotations) != 2 {
+		t.Fatalf("Expected the existing rotation to be adopted and one rotation to be created, got %+v", rotations.Rotations)
+	}
+	if id := applied.Attributes["rotation.0.id"]; id != rotation.Id {
+		t.Errorf("Expected the weekdays block to adopt rotation %s, got %s", rotation.Id, id)
+	}
+	if name := applied.Attributes["rotation.1.name"]; name != "weekend" || applied.Attributes["rotation.1.id"] == "" {
+		t.Errorf("Expected the weekend rotation to be created, got %v", applied.Attributes)
+	}
+}
+
+func TestResourceOpsgenieScheduleUpdate_rotationIdentity(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := schedule.NewClient(meta.client.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotation := func(name, startDate string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        name,
+			"start_date":  startDate,
+			"type":        "weekly",
+			"participant": []interface{}{map[string]interface{}{"type": "user", "username": "jane@example.com"}},
+		}
+	}
+	config := func(rotations ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":     "platform",
+			"timezone": "Europe/Rome",
+			"rotation": rotations,
+		})
+	}
+	apply := func(state *terraform.InstanceState, config *terraform.ResourceConfig) *terraform.InstanceState {
+		r := resourceOpsgenieSchedule()
+		diff, err := r.SimpleDiff(ctx, state, config, meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		applied, diags := r.Apply(ctx, state, diff, meta)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return applied
+	}
+	// rotationIds returns the id of each rotation of the schedule by its
+	// name, and checks that the start date still belongs to that name
+	rotationIds := func(state *terraform.InstanceState, startDates map[string]string) map[string]string {
+		rotations, err := client.ListRotations(ctx, &schedule.ListRotationsRequest{
+			ScheduleIdentifierType:  schedule.Id,
+			ScheduleIdentifierValue: state.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make(map[string]string, len(rotations.Rotations))
+		for _, r := range rotations.Rotations {
+			ids[r.Name] = r.Id
+			if startDate := r.StartDate.UTC().Format(time.RFC3339); startDate != startDates[r.Name] {
+				t.Errorf("Expected rotation %s to start at %s, got %s", r.Name, startDates[r.Name], startDate)
+			}
+		}
+		return ids
+	}
+	startDates := map[string]string{
+		"first":  "2021-01-04T09:00:00Z",
+		"second": "2021-01-05T09:00:00Z",
+		"third":  "2021-01-06T09:00:00Z",
+	}
+
+	state := apply(&terraform.InstanceState{}, config(
+		rotation("first", startDates["first"]),
+		rotation("second", startDates["second"]),
+		rotation("third", startDates["third"]),
+	))
+	created := rotationIds(state, startDates)
+
+	// removing the middle block deletes its rotation only
+	state = apply(state, config(
+		rotation("first", startDates["first"]),
+		rotation("third", startDates["third"]),
+	))
+	ids := rotationIds(state, startDates)
+	if len(ids) != 2 || ids["first"] != created["first"] || ids["third"] != created["third"] {
+		t.Errorf("Expected only the second rotation to be deleted, got %v from %v", ids, created)
+	}
+	if state.Attributes["rotation.1.name"] != "third" || state.Attributes["rotation.1.id"] != created["third"] {
+		t.Errorf("Expected the second block to keep the third rotation, got %v", state.Attributes)
+	}
+
+	// inserting a block in the middle creates its rotation only
+	state = apply(state, config(
+		rotation("first", startDates["first"]),
+		rotation("second", startDates["second"]),
+		rotation("third", startDates["third"]),
+	))
+	ids = rotationIds(state, startDates)
+	if len(ids) != 3 || ids["first"] != created["first"] || ids["third"] != created["third"] {
+		t.Errorf("Expected only the second rotation to be created, got %v from %v", ids, created)
+	}
+	for i, name := range []string{"first", "second", "third"} {
+		if state.Attributes[fmt.Sprintf("rotation.%d.name", i)] != name || state.Attributes[fmt.Sprintf("rotation.%d.id", i)] != ids[name] {
+			t.Errorf("Expected block %d to hold rotation %s, got %v", i, name, state.Attributes)
+		}
+	}
+}
+
+func TestMatchScheduleRotationBlocks(t *testing.T) {
+	prior := []interface{}{
+		map[string]interface{}{"id": "1", "name": "first"},
+		map[string]interface{}{"id": "2", "name": "second"},
+		map[string]interface{}{"id": "3", "name": "third"},
+	}
+	block := func(id, name string) interface{} {
+		return map[string]interface{}{"id": id, "name": name}
+	}
+	cases := []struct {
+		name      string
+		rotations []interface{}
+		expected  []string
+	}{
+		{"unchanged", []interface{}{block("1", "first"), block("2", "second"), block("3", "third")}, []string{"1", "2", "3"}},
+		{"middle removed", []interface{}{block("1", "first"), block("2", "third")}, []string{"1", "3"}},
+		{"reordered", []interface{}{block("1", "third"), block("2", "first"), block("3", "second")}, []string{"3", "1", "2"}},
+		{"renamed", []interface{}{block("1", "first"), block("2", "renamed"), block("3", "third")}, []string{"1", "2", "3"}},
+		{"middle replaced", []interface{}{block("1", "first"), block("2", "new"), block("3", "second")}, []string{"1", "", "2"}},
+		{"added", []interface{}{block("1", "first"), block("2", "second"), block("3", "third"), block("", "fourth")}, []string{"1", "2", "3", ""}},
+	}
+	for _, c := range cases {
+		if ids := matchScheduleRotationBlocks(prior, c.rotations); fmt.Sprint(ids) != fmt.Sprint(c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, ids)
+		}
+	}
+}
@@ -3,10 +3,12 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/alert"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,12 +18,12 @@ import (
 
 func resourceOpsGenieServiceIncidentRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieServiceIncidentRuleCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieServiceIncidentRuleRead),
-		Update: resourceOpsGenieServiceIncidentRuleUpdate,
-		Delete: resourceOpsGenieServiceIncidentRuleDelete,
+		CreateContext: resourceOpsGenieServiceIncidentRuleCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieServiceIncidentRuleRead),
+		UpdateContext: resourceOpsGenieServiceIncidentRuleUpdate,
+		DeleteContext: resourceOpsGenieServiceIncidentRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), "/")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("Unexpected format of ID (%q), expected service_id/service_incident_rule_id", d.Id())
@@ -31,6 +33,12 @@ func resourceOpsGenieServiceIncidentRule() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"service_id": {
 				Type:         schema.TypeString,
@@ -156,10 +164,10 @@ func resourceOpsGenieServiceIncidentRule() *schema.Resource {
 	}
 }
 
-func resourceOpsGenieServiceIncidentRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieServiceIncidentRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := service.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	service_id := d.Get("service_id").(string)
@@ -176,17 +184,17 @@ func resourceOpsGenieServiceIncidentRuleCreate(d *schema.ResourceData, meta inte
 	}
 
 	log.Printf("[INFO] Creating OpsGenie Service Incident Rule for service '%s'", d.Get("service_id").(string))
-	result, err := client.CreateIncidentRule(context.Background(), createRequest)
+	result, err := client.CreateIncidentRule(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsGenieServiceIncidentRuleRead(d, meta)
+	return diag.FromErr(resourceOpsGenieServiceIncidentRuleRead(ctx, d, meta))
 }
 
-func resourceOpsGenieServiceIncidentRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieServiceIncidentRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := service.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -196,7 +204,7 @@ func resourceOpsGenieServiceIncidentRuleRead(d *schema.ResourceData, meta interf
 
 	log.Printf("[INFO] Reading OpsGenie Service Incident Rule for service: '%s' for rule ID: '%s'", service_id, incident_rule_id)
 
-	incident_rule_res, err := client.GetIncidentRules(context.Background(), &service.GetIncidentRulesRequest{
+	incident_rule_res, err := client.GetIncidentRules(ctx, &service.GetIncidentRulesRequest{
 		ServiceId: service_id,
 	})
 	if err != nil {
@@ -217,10 +225,10 @@ func resourceOpsGenieServiceIncidentRuleRead(d *schema.ResourceData, meta interf
 	return nil
 }
 
-func resourceOpsGenieServiceIncidentRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieServiceIncidentRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := service.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	service_id := d.Get("service_id").(string)
@@ -240,31 +248,31 @@ func resourceOpsGenieServiceIncidentRuleUpdate(d *schema.ResourceData, meta inte
 	}
 
 	log.Printf("[INFO] Updating Service Incident Rule for service: '%s' for rule ID: '%s'", service_id, incident_rule_id)
-	_, err = client.UpdateIncidentRule(context.Background(), updateRequest)
+	_, err = client.UpdateIncidentRule(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsGenieServiceIncidentRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieServiceIncidentRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	service_id := d.Get("service_id").(string)
 	incident_rule_id := d.Id()
 
 	log.Printf("[INFO] Deleting OpsGenie ervice Incident Rule for service: '%s' for rule ID: '%s'", service_id, incident_rule_id)
 	client, err := service.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &service.DeleteIncidentRuleRequest{
 		ServiceId:      service_id,
 		IncidentRuleId: incident_rule_id,
 	}
 
-	_, err = client.DeleteIncidentRule(context.Background(), deleteRequest)
+	_, err = client.DeleteIncidentRule(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -24,7 +23,7 @@ func init() {
 }
 
 func testSweepServiceIncidentRule(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,15 +38,24 @@ func testSweepServiceIncidentRule(region string) error {
 	}
 
 	for _, svc := range resp.Services {
-		if strings.HasPrefix(svc.Name, "genietest-") {
-			log.Printf("Destroying service %s", svc.Name)
-
-			deleteRequest := service.DeleteRequest{
-				Id: svc.Id,
+		if isSweepable(svc.Name) {
+			rules, err := client.GetIncidentRules(context.Background(), &service.GetIncidentRulesRequest{
+				ServiceId: svc.Id,
+			})
+			if err != nil {
+				return err
 			}
+			for _, rule := range rules.IncidentRule {
+				log.Printf("Destroying incident rule %s of service %s", rule.Id, svc.Name)
 
-			if _, err := client.Delete(context.Background(), &deleteRequest); err != nil {
-				return err
+				deleteRequest := service.DeleteIncidentRuleRequest{
+					ServiceId:      svc.Id,
+					IncidentRuleId: rule.Id,
+				}
+
+				if _, err := client.DeleteIncidentRule(context.Background(), &deleteRequest); err != nil {
+					return err
+				}
 			}
 		}
 	}
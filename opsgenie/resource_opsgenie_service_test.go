@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -18,13 +17,17 @@ import (
 func init() {
 	resource.AddTestSweepers("opsgenie_service", &resource.Sweeper{
 		Name: "opsgenie_service",
-		F:    testSweepService,
+		Dependencies: []string{
+			"opsgenie_service_incident_rule",
+			"opsgenie_incident_template",
+		},
+		F: testSweepService,
 	})
 
 }
 
 func testSweepService(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +42,7 @@ func testSweepService(region string) error {
 	}
 
 	for _, svc := range resp.Services {
-		if strings.HasPrefix(svc.Name, "genietest-") {
+		if isSweepable(svc.Name) {
 			log.Printf("Destroying service %s", svc.Name)
 
 			deleteRequest := service.DeleteRequest{
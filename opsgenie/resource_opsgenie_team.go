@@ -2,11 +2,14 @@ package opsgenie
 
 import (
 	"context"
-	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/escalation"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/schedule"
 	"log"
+	"time"
 
 	"fmt"
 	"regexp"
@@ -17,12 +20,22 @@ import (
 
 func resourceOpsGenieTeam() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieTeamCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieTeamRead),
-		Update: resourceOpsGenieTeamUpdate,
-		Delete: resourceOpsGenieTeamDelete,
+		CreateContext: resourceOpsGenieTeamCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieTeamRead),
+		UpdateContext: resourceOpsGenieTeamUpdate,
+		DeleteContext: resourceOpsGenieTeamDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceOpsGenieTeamImport,
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffTeamDefaultResources,
+			customizeDiffEscalationRules("default_escalation.0.rules"),
+		),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -39,9 +52,123 @@ func resourceOpsGenieTeam() *schema.Resource {
 				Optional: true,
 			},
 			"delete_default_resources": {
-				Type:     schema.TypeBool,
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"adopt_default_resources"},
+			},
+			"adopt_default_resources": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"delete_default_resources"},
+			},
+			"default_schedule_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_escalation_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_routing_rule_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_schedule": {
+				Type:     schema.TypeList,
 				Optional: true,
-				Default:  false,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateOpsgenieScheduleDescription,
+						},
+						"timezone": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"default_escalation": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"rules": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem:     resourceOpsgenieEscalationRule(),
+							Set:      escalationRuleHash,
+						},
+						"repeat": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem:     resourceOpsgenieEscalationRepeat(),
+						},
+					},
+				},
+			},
+			"default_routing_rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"timezone": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"notify": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"schedule", "escalation", "none"}, false),
+									},
+									"id": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
 			},
 			"member": {
 				Type:     schema.TypeList,
@@ -65,10 +192,10 @@ func resourceOpsGenieTeam() *schema.Resource {
 	}
 }
 
-func resourceOpsGenieTeamCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -84,9 +211,9 @@ func resourceOpsGenieTeamCreate(d *schema.ResourceData, meta interface{}) error
 
 	log.Printf("[INFO] Creating OpsGenie team %q", name)
 
-	_, err = client.Create(context.Background(), createRequest)
+	_, err = client.Create(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	getRequest := &team.GetTeamRequest{
@@ -94,9 +221,9 @@ func resourceOpsGenieTeamCreate(d *schema.ResourceData, meta interface{}) error
 		IdentifierValue: name,
 	}
 
-	getResponse, err := client.Get(context.Background(), getRequest)
+	getResponse, err := client.Get(ctx, getRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(getResponse.Id)
@@ -104,25 +231,59 @@ func resourceOpsGenieTeamCreate(d *schema.ResourceData, meta interface{}) error
 	shouldDeleteDefaultResources := d.Get("delete_default_resources").(bool)
 
 	if shouldDeleteDefaultResources {
-		err = findAndUpdateDefaultRoutingRule(name, meta.(*OpsgenieClient).client.Config)
+		err = findAndUpdateDefaultRoutingRule(ctx, name, meta.(*OpsgenieClient).client.Config)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		err := findAndDeleteDefaultEscalation(name, meta.(*OpsgenieClient).client.Config)
+		err := findAndDeleteDefaultEscalation(ctx, name, meta.(*OpsgenieClient).client.Config)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		err = findAndDeleteDefaultSchedule(name, meta.(*OpsgenieClient).client.Config)
+		err = findAndDeleteDefaultSchedule(ctx, name, meta.(*OpsgenieClient).client.Config)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("adopt_default_resources").(bool) {
+		err = findAndAdoptDefaultResources(ctx, d, meta.(*OpsgenieClient).client.Config)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = updateAdoptedDefaultResources(ctx, d, meta.(*OpsgenieClient).client.Config)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	return resourceOpsGenieTeamRead(d, meta)
+	return diag.FromErr(resourceOpsGenieTeamRead(ctx, d, meta))
 }
 
-func resourceOpsGenieTeamRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, ok := importName(d.Id())
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &team.GetTeamRequest{
+		IdentifierType:  team.Name,
+		IdentifierValue: name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not find team %q to import: %s", name, err)
+	}
+
+	d.SetId(result.Id)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceOpsGenieTeamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -135,7 +296,7 @@ func resourceOpsGenieTeamRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[INFO] Retrieving state of OpsGenie team '%s'", d.Get("name"))
 
-	getResponse, err := client.Get(context.Background(), getRequest)
+	getResponse, err := client.Get(ctx, getRequest)
 	if err != nil {
 		return err
 	}
@@ -147,13 +308,24 @@ func resourceOpsGenieTeamRead(d *schema.ResourceData, meta interface{}) error {
 		d.Set("member", flattenOpsGenieTeamMembers(getResponse.Members))
 	}
 
+	if d.Get("adopt_default_resources").(bool) {
+		return readAdoptedDefaultResources(ctx, d, meta.(*OpsgenieClient).client.Config)
+	}
+
+	d.Set("default_schedule_id", "")
+	d.Set("default_escalation_id", "")
+	d.Set("default_routing_rule_id", "")
+	d.Set("default_schedule", nil)
+	d.Set("default_escalation", nil)
+	d.Set("default_routing_rule", nil)
+
 	return nil
 }
 
-func resourceOpsGenieTeamUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
@@ -170,33 +342,77 @@ func resourceOpsGenieTeamUpdate(d *schema.ResourceData, meta interface{}) error
 
 	log.Printf("[INFO] Updating OpsGenie team '%s'", name)
 
-	_, err = client.Update(context.Background(), updateRequest)
+	_, err = client.Update(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return nil
+	if d.Get("adopt_default_resources").(bool) {
+		if d.HasChange("adopt_default_resources") {
+			err = findAndAdoptDefaultResources(ctx, d, meta.(*OpsgenieClient).client.Config)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		if d.HasChanges("adopt_default_resources", "default_schedule", "default_escalation", "default_routing_rule") {
+			err = updateAdoptedDefaultResources(ctx, d, meta.(*OpsgenieClient).client.Config)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return diag.FromErr(resourceOpsGenieTeamRead(ctx, d, meta))
 }
 
-func resourceOpsGenieTeamDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie team '%s'", d.Get("name").(string))
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &team.DeleteTeamRequest{
 		IdentifierType:  team.Id,
 		IdentifierValue: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
+// customizeDiffTeamDefaultResources rejects default_* blocks on teams that
+// do not adopt their default resources, as they would never be applied. When
+// adopt_default_resources changes, the ids of the default resources are only
+// known after the apply.
+func customizeDiffTeamDefaultResources(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("adopt_default_resources") {
+		return nil
+	}
+	// the blocks of a team that adopted its default resources stay in the
+	// state until it is read again, and are not distinguished from configured
+	// blocks here
+	if adopted, adopt := d.GetChange("adopt_default_resources"); !adopted.(bool) && !adopt.(bool) {
+		for _, k := range []string{"default_schedule", "default_escalation", "default_routing_rule"} {
+			if len(d.Get(k).([]interface{})) > 0 {
+				return fmt.Errorf("%s can only be set when adopt_default_resources is true", k)
+			}
+		}
+	}
+	if d.Id() != "" && d.HasChange("adopt_default_resources") {
+		for _, k := range []string{"default_schedule_id", "default_escalation_id", "default_routing_rule_id"} {
+			if err := d.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func flattenOpsGenieTeamMembers(input []team.Member) []map[string]interface{} {
 	members := make([]map[string]interface{}, 0, len(input))
 	for _, inputMember := range input {
@@ -250,71 +466,97 @@ func validateOpsGenieTeamName(v interface{}, k string) (ws []string, errors []er
 	return
 }
 
-func findAndDeleteDefaultSchedule(teamName string, config *client.Config) error {
+func findAndDeleteDefaultSchedule(ctx context.Context, teamName string, config *client.Config) error {
 	scheduleClient, err := schedule.NewClient(config)
 	if err != nil {
 		return err
 	}
+	schedules, err := findOpsGenieTeamSchedules(ctx, teamName, config)
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 {
+		log.Printf("[WARN] Could not find any schedule for OpsGenie team '%s', nothing to delete", teamName)
+		return nil
+	}
+	_, err = scheduleClient.Delete(ctx, &schedule.DeleteRequest{
+		IdentifierType:  schedule.Id,
+		IdentifierValue: schedules[0].Id,
+	})
+	return err
+}
+
+func findAndDeleteDefaultEscalation(ctx context.Context, teamName string, config *client.Config) error {
+	escalationClient, err := escalation.NewClient(config)
+	if err != nil {
+		return err
+	}
+	escalations, err := findOpsGenieTeamEscalations(ctx, teamName, config)
+	if err != nil {
+		return err
+	}
+	if len(escalations) == 0 {
+		log.Printf("[WARN] Could not find any escalation for OpsGenie team '%s', nothing to delete", teamName)
+		return nil
+	}
+	_, err = escalationClient.Delete(ctx, &escalation.DeleteRequest{
+		IdentifierType: escalation.Id,
+		Identifier:     escalations[0].Id,
+	})
+	return err
+}
+
+// findOpsGenieTeamSchedules returns the schedules whose owner team is teamName.
+func findOpsGenieTeamSchedules(ctx context.Context, teamName string, config *client.Config) ([]schedule.Schedule, error) {
+	scheduleClient, err := schedule.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
 	expand := true
-	res, err := scheduleClient.List(context.Background(), &schedule.ListRequest{
+	res, err := scheduleClient.List(ctx, &schedule.ListRequest{
 		Expand: &expand,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	schedules := make([]schedule.Schedule, 0)
 	for _, sched := range res.Schedule {
 		ownerTeam := sched.OwnerTeam
-		if ownerTeam != nil {
-			if ownerTeam.Name == teamName {
-				_, err = scheduleClient.Delete(context.Background(), &schedule.DeleteRequest{
-					IdentifierType:  schedule.Id,
-					IdentifierValue: sched.Id,
-				})
-				if err != nil {
-					return err
-				}
-				return nil
-			}
+		if ownerTeam != nil && ownerTeam.Name == teamName {
+			schedules = append(schedules, sched)
 		}
 	}
 
-	return errors.New("Could not find any schedule name for this team")
+	return schedules, nil
 }
 
-func findAndDeleteDefaultEscalation(teamName string, config *client.Config) error {
+// findOpsGenieTeamEscalations returns the escalations whose owner team is teamName.
+func findOpsGenieTeamEscalations(ctx context.Context, teamName string, config *client.Config) ([]escalation.Escalation, error) {
 	escalationClient, err := escalation.NewClient(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	res, err := escalationClient.List(context.Background())
+	res, err := escalationClient.List(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	escalations := make([]escalation.Escalation, 0)
 	for _, escal := range res.Escalations {
 		ownerTeam := escal.OwnerTeam
-		if ownerTeam != nil {
-			if ownerTeam.Name == teamName {
-				_, err = escalationClient.Delete(context.Background(), &escalation.DeleteRequest{
-					IdentifierType: escalation.Id,
-					Identifier:     escal.Id,
-				})
-				if err != nil {
-					return err
-				}
-				return nil
-			}
+		if ownerTeam != nil && ownerTeam.Name == teamName {
+			escalations = append(escalations, escal)
 		}
 	}
 
-	return errors.New("Could not find any escalation for this team")
+	return escalations, nil
 }
 
-func findAndUpdateDefaultRoutingRule(teamName string, config *client.Config) error {
+func findAndUpdateDefaultRoutingRule(ctx context.Context, teamName string, config *client.Config) error {
 	teamClient, err := team.NewClient(config)
 	if err != nil {
 		return err
 	}
-	rules, err := teamClient.ListRoutingRules(context.Background(), &team.ListRoutingRulesRequest{
+	rules, err := teamClient.ListRoutingRules(ctx, &team.ListRoutingRulesRequest{
 		TeamIdentifierType:  team.Name,
 		TeamIdentifierValue: teamName,
 	})
@@ -323,7 +565,7 @@ func findAndUpdateDefaultRoutingRule(teamName string, config *client.Config) err
 	}
 
 	for _, rule := range rules.RoutingRules {
-		_, err := teamClient.UpdateRoutingRule(context.Background(), &team.UpdateRoutingRuleRequest{
+		_, err := teamClient.UpdateRoutingRule(ctx, &team.UpdateRoutingRuleRequest{
 			TeamIdentifierType:  team.Name,
 			TeamIdentifierValue: teamName,
 			RoutingRuleId:       rule.Id,
@@ -338,3 +580,247 @@ func findAndUpdateDefaultRoutingRule(teamName string, config *client.Config) err
 	}
 	return nil
 }
+
+// findAndAdoptDefaultResources looks up the schedule, escalation and routing
+// rule Opsgenie creates along with a team and stores their IDs.
+func findAndAdoptDefaultResources(ctx context.Context, d *schema.ResourceData, config *client.Config) error {
+	teamName := d.Get("name").(string)
+
+	schedules, err := findOpsGenieTeamSchedules(ctx, teamName, config)
+	if err != nil {
+		return err
+	}
+	scheduleId := ""
+	for _, sched := range schedules {
+		if scheduleId == "" || sched.Name == teamName+"_schedule" {
+			scheduleId = sched.Id
+		}
+	}
+
+	escalations, err := findOpsGenieTeamEscalations(ctx, teamName, config)
+	if err != nil {
+		return err
+	}
+	escalationId := ""
+	for _, escal := range escalations {
+		if escalationId == "" || escal.Name == teamName+"_escalation" {
+			escalationId = escal.Id
+		}
+	}
+
+	teamClient, err := team.NewClient(config)
+	if err != nil {
+		return err
+	}
+	rules, err := teamClient.ListRoutingRules(ctx, &team.ListRoutingRulesRequest{
+		TeamIdentifierType:  team.Id,
+		TeamIdentifierValue: d.Id(),
+	})
+	if err != nil {
+		return err
+	}
+	routingRuleId := ""
+	for _, rule := range rules.RoutingRules {
+		if rule.IsDefault {
+			routingRuleId = rule.Id
+		}
+	}
+
+	d.Set("default_schedule_id", scheduleId)
+	d.Set("default_escalation_id", escalationId)
+	d.Set("default_routing_rule_id", routingRuleId)
+
+	log.Printf("[INFO] Adopted default resources of OpsGenie team '%s': schedule '%s', escalation '%s', routing rule '%s'",
+		teamName, scheduleId, escalationId, routingRuleId)
+
+	return nil
+}
+
+// updateAdoptedDefaultResources applies the configured default_* blocks to
+// the adopted resources. Unset attributes are left as they are in Opsgenie.
+func updateAdoptedDefaultResources(ctx context.Context, d *schema.ResourceData, config *client.Config) error {
+	scheduleId := d.Get("default_schedule_id").(string)
+	if input := d.Get("default_schedule").([]interface{}); scheduleId != "" && len(input) > 0 && input[0] != nil {
+		scheduleClient, err := schedule.NewClient(config)
+		if err != nil {
+			return err
+		}
+		current, err := scheduleClient.Get(ctx, &schedule.GetRequest{
+			IdentifierType:  schedule.Id,
+			IdentifierValue: scheduleId,
+		})
+		if err != nil {
+			return err
+		}
+
+		inputMap := input[0].(map[string]interface{})
+		enabled := inputMap["enabled"].(bool)
+		updateRequest := &schedule.UpdateRequest{
+			IdentifierType:  schedule.Id,
+			IdentifierValue: scheduleId,
+			Name:            current.Schedule.Name,
+			Description:     inputMap["description"].(string),
+			Timezone:        inputMap["timezone"].(string),
+			Enabled:         &enabled,
+			OwnerTeam:       current.Schedule.OwnerTeam,
+		}
+
+		log.Printf("[INFO] Updating default schedule '%s' of OpsGenie team '%s'", current.Schedule.Name, d.Get("name"))
+
+		_, err = scheduleClient.Update(ctx, updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
+	escalationId := d.Get("default_escalation_id").(string)
+	if input := d.Get("default_escalation").([]interface{}); escalationId != "" && len(input) > 0 && input[0] != nil {
+		escalationClient, err := escalation.NewClient(config)
+		if err != nil {
+			return err
+		}
+
+		inputMap := input[0].(map[string]interface{})
+		if err := validateEscalationRules("default_escalation.0.rules", inputMap["rules"].(*schema.Set).List()); err != nil {
+			return err
+		}
+		updateRequest := &escalation.UpdateRequest{
+			IdentifierType: escalation.Id,
+			Identifier:     escalationId,
+			Description:    inputMap["description"].(string),
+			Rules:          expandOpsgenieEscalationRuleSet(inputMap["rules"].(*schema.Set)),
+		}
+		if repeat := inputMap["repeat"].([]interface{}); len(repeat) > 0 {
+			updateRequest.Repeat = expandOpsgenieEscalationRepeat(repeat)
+		}
+
+		log.Printf("[INFO] Updating default escalation '%s' of OpsGenie team '%s'", escalationId, d.Get("name"))
+
+		_, err = escalationClient.Update(ctx, updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
+	routingRuleId := d.Get("default_routing_rule_id").(string)
+	if input := d.Get("default_routing_rule").([]interface{}); routingRuleId != "" && len(input) > 0 && input[0] != nil {
+		teamClient, err := team.NewClient(config)
+		if err != nil {
+			return err
+		}
+
+		inputMap := input[0].(map[string]interface{})
+		updateRequest := &team.UpdateRoutingRuleRequest{
+			TeamIdentifierType:  team.Id,
+			TeamIdentifierValue: d.Id(),
+			RoutingRuleId:       routingRuleId,
+			Timezone:            inputMap["timezone"].(string),
+		}
+		if notify := inputMap["notify"].([]interface{}); len(notify) > 0 {
+			updateRequest.Notify = expandOpsgenieNotify(notify)
+		}
+
+		log.Printf("[INFO] Updating default routing rule '%s' of OpsGenie team '%s'", routingRuleId, d.Get("name"))
+
+		_, err = teamClient.UpdateRoutingRule(ctx, updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readAdoptedDefaultResources refreshes the default_* blocks from Opsgenie.
+// Adopted resources that have been deleted are dropped from the state.
+func readAdoptedDefaultResources(ctx context.Context, d *schema.ResourceData, config *client.Config) error {
+	if d.Get("default_schedule_id").(string) == "" && d.Get("default_escalation_id").(string) == "" && d.Get("default_routing_rule_id").(string) == "" {
+		if err := findAndAdoptDefaultResources(ctx, d, config); err != nil {
+			return err
+		}
+	}
+
+	// keep how recipients were referenced before the blocks are refreshed
+	var priorEscalationRules []interface{}
+	if rules, ok := d.Get("default_escalation.0.rules").(*schema.Set); ok {
+		priorEscalationRules = rules.List()
+	}
+	priorRoutingRuleNotify := d.Get("default_routing_rule.0.notify").([]interface{})
+
+	d.Set("default_schedule", nil)
+	if scheduleId := d.Get("default_schedule_id").(string); scheduleId != "" {
+		scheduleClient, err := schedule.NewClient(config)
+		if err != nil {
+			return err
+		}
+		result, err := scheduleClient.Get(ctx, &schedule.GetRequest{
+			IdentifierType:  schedule.Id,
+			IdentifierValue: scheduleId,
+		})
+		if isOpsGenieNotFound(err) {
+			d.Set("default_schedule_id", "")
+		} else if err != nil {
+			return err
+		} else {
+			d.Set("default_schedule", []map[string]interface{}{
+				{
+					"description": result.Schedule.Description,
+					"timezone":    result.Schedule.Timezone,
+					"enabled":     result.Schedule.Enabled,
+				},
+			})
+		}
+	}
+
+	d.Set("default_escalation", nil)
+	if escalationId := d.Get("default_escalation_id").(string); escalationId != "" {
+		escalationClient, err := escalation.NewClient(config)
+		if err != nil {
+			return err
+		}
+		result, err := escalationClient.Get(ctx, &escalation.GetRequest{
+			IdentifierType: escalation.Id,
+			Identifier:     escalationId,
+		})
+		if isOpsGenieNotFound(err) {
+			d.Set("default_escalation_id", "")
+		} else if err != nil {
+			return err
+		} else {
+			d.Set("default_escalation", []map[string]interface{}{
+				{
+					"description": result.Description,
+					"rules":       normalizeEscalationRuleRecipients(flattenOpsgenieEscalationRules(result.Rules), priorEscalationRules),
+					"repeat":      flattenOpsgenieEscalationRepeat(result.Repeat),
+				},
+			})
+		}
+	}
+
+	d.Set("default_routing_rule", nil)
+	if routingRuleId := d.Get("default_routing_rule_id").(string); routingRuleId != "" {
+		teamClient, err := team.NewClient(config)
+		if err != nil {
+			return err
+		}
+		result, err := teamClient.GetRoutingRule(ctx, &team.GetRoutingRuleRequest{
+			TeamIdentifierType:  team.Id,
+			TeamIdentifierValue: d.Id(),
+			RoutingRuleId:       routingRuleId,
+		})
+		if isOpsGenieNotFound(err) {
+			d.Set("default_routing_rule_id", "")
+		} else if err != nil {
+			return err
+		} else {
+			d.Set("default_routing_rule", []map[string]interface{}{
+				{
+					"timezone": result.Timezone,
+					"notify":   normalizeResponderIdentifiers(flattenOpsgenieNotify(result.Notify), priorRoutingRuleNotify),
+				},
+			})
+		}
+	}
+
+	return nil
+}
@@ -3,24 +3,27 @@ package opsgenie
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/opsgenie/opsgenie-go-sdk-v2/og"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
 )
 
 func resourceOpsGenieTeamRoutingRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieTeamRoutingRuleCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieTeamRoutingRuleRead),
-		Update: resourceOpsGenieTeamRoutingRuleUpdate,
-		Delete: resourceOpsGenieTeamRoutingRuleDelete,
+		CreateContext: resourceOpsGenieTeamRoutingRuleCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieTeamRoutingRuleRead),
+		UpdateContext: resourceOpsGenieTeamRoutingRuleUpdate,
+		DeleteContext: resourceOpsGenieTeamRoutingRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), "/")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("Unexpected format of ID (%q), expected team_id/routing_rule_id", d.Id())
@@ -32,6 +35,16 @@ func resourceOpsGenieTeamRoutingRule() *schema.Resource {
 				return []*schema.ResourceData{d}, nil
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffFilter("criteria"),
+			customizeDiffTimeRestriction("time_restriction"),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -46,8 +59,11 @@ func resourceOpsGenieTeamRoutingRule() *schema.Resource {
 				Optional: true,
 			},
 			"timezone": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimezone,
+				DiffSuppressFunc: checkTimeZoneDiff,
 			},
 			"notify": {
 				Type:     schema.TypeList,
@@ -190,10 +206,10 @@ func resourceOpsGenieTeamRoutingRule() *schema.Resource {
 	}
 }
 
-func resourceOpsGenieTeamRoutingRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamRoutingRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	teamId := d.Get("team_id").(string)
@@ -205,7 +221,7 @@ func resourceOpsGenieTeamRoutingRuleCreate(d *schema.ResourceData, meta interfac
 
 	expandedCriteria := expandOpsgenieCriteria(criteria)
 	if err := validateOpsgenieCriteria(expandedCriteria); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	createRequest := &team.CreateRoutingRuleRequest{
@@ -224,16 +240,16 @@ func resourceOpsGenieTeamRoutingRuleCreate(d *schema.ResourceData, meta interfac
 
 	log.Printf("[INFO] Creating OpsGenie team routing rule '%s'", name)
 
-	result, err := client.CreateRoutingRule(context.Background(), createRequest)
+	result, err := client.CreateRoutingRule(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(result.Id)
 
-	return resourceOpsGenieTeamRoutingRuleRead(d, meta)
+	return diag.FromErr(resourceOpsGenieTeamRoutingRuleRead(ctx, d, meta))
 }
 
-func resourceOpsGenieTeamRoutingRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamRoutingRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -245,24 +261,24 @@ func resourceOpsGenieTeamRoutingRuleRead(d *schema.ResourceData, meta interface{
 		RoutingRuleId:       d.Id(),
 	}
 
-	result, err := client.GetRoutingRule(context.Background(), getRequest)
+	result, err := client.GetRoutingRule(ctx, getRequest)
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", result.Name)
 	d.Set("time_restriction", flattenOpsgenieTimeRestriction(result.TimeRestriction))
-	d.Set("notify", flattenOpsgenieNotify(result.Notify))
+	d.Set("notify", normalizeResponderIdentifiers(flattenOpsgenieNotify(result.Notify), d.Get("notify").([]interface{})))
 	d.Set("criteria", flattenOpsgenieCriteria(result.Criteria))
 	d.Set("timezone", result.Timezone)
 
 	return nil
 }
 
-func resourceOpsGenieTeamRoutingRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamRoutingRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	name := d.Get("name").(string)
 	teamId := d.Get("team_id").(string)
@@ -273,7 +289,7 @@ func resourceOpsGenieTeamRoutingRuleUpdate(d *schema.ResourceData, meta interfac
 
 	expandedCriteria := expandOpsgenieCriteria(criteria)
 	if err := validateOpsgenieCriteria(expandedCriteria); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	updateRequest := &team.UpdateRoutingRuleRequest{
@@ -291,19 +307,19 @@ func resourceOpsGenieTeamRoutingRuleUpdate(d *schema.ResourceData, meta interfac
 
 	log.Printf("[INFO] Updating OpsGenie team routing rule '%s'", name)
 
-	_, err = client.UpdateRoutingRule(context.Background(), updateRequest)
+	_, err = client.UpdateRoutingRule(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsGenieTeamRoutingRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieTeamRoutingRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie team routing rule'%s'", d.Get("name").(string))
 	client, err := team.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &team.DeleteRoutingRuleRequest{
 		TeamIdentifierType:  team.Id,
@@ -311,9 +327,9 @@ func resourceOpsGenieTeamRoutingRuleDelete(d *schema.ResourceData, meta interfac
 		RoutingRuleId:       d.Id(),
 	}
 
-	_, err = client.DeleteRoutingRule(context.Background(), deleteRequest)
+	_, err = client.DeleteRoutingRule(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
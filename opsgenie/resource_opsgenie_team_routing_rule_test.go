@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -17,14 +16,14 @@ import (
 
 func init() {
 	resource.AddTestSweepers("opsgenie_team_routing_rule", &resource.Sweeper{
-		Name: "opsgenie_team_routing_roule",
+		Name: "opsgenie_team_routing_rule",
 		F:    testSweepTeamRoutingRule,
 	})
 
 }
 
 func testSweepTeamRoutingRule(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,8 +38,8 @@ func testSweepTeamRoutingRule(region string) error {
 	}
 
 	for _, u := range resp.Teams {
-		if strings.HasPrefix(u.Name, "genieteam") {
-			log.Printf("Destroying team routing rule %s", u.Name)
+		if isSweepable(u.Name) {
+			log.Printf("Destroying team routing rules of team %s", u.Name)
 			resp2, err := client.ListRoutingRules(context.Background(), &team.ListRoutingRulesRequest{
 				TeamIdentifierType:  team.Id,
 				TeamIdentifierValue: u.Id,
@@ -49,6 +48,10 @@ func testSweepTeamRoutingRule(region string) error {
 				return err
 			}
 			for _, k := range resp2.RoutingRules {
+				// the default routing rule of a team cannot be deleted
+				if k.IsDefault {
+					continue
+				}
 
 				deleteRequest := team.DeleteRoutingRuleRequest{
 					TeamIdentifierType:  team.Id,
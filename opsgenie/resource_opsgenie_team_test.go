@@ -10,21 +10,34 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	ogClient "github.com/opsgenie/opsgenie-go-sdk-v2/client"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/team"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
 )
 
 func init() {
 	resource.AddTestSweepers("opsgenie_team", &resource.Sweeper{
 		Name: "opsgenie_team",
-		F:    testSweepTeam,
+		Dependencies: []string{
+			"opsgenie_team_routing_rule",
+			"opsgenie_alert_policy",
+			"opsgenie_notification_policy",
+			"opsgenie_schedule",
+			"opsgenie_escalation",
+			"opsgenie_heartbeat",
+			"opsgenie_service",
+			"opsgenie_api_integration",
+			"opsgenie_email_integration",
+		},
+		F: testSweepTeam,
 	})
 
 }
 
 func testSweepTeam(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +52,7 @@ func testSweepTeam(region string) error {
 	}
 
 	for _, u := range resp.Teams {
-		if strings.HasPrefix(u.Name, "genieteam") {
+		if isSweepable(u.Name) {
 			log.Printf("Destroying team %s", u.Name)
 
 			deleteRequest := team.DeleteTeamRequest{
@@ -70,6 +83,16 @@ func TestAccOpsGenieTeam_basic(t *testing.T) {
 					testCheckOpsGenieTeamExists("opsgenie_team.test"),
 				),
 			},
+			{
+				ResourceName:      "opsgenie_team.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("name:genieteam-%s", rs),
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"delete_default_resources",
+					"adopt_default_resources",
+				},
+			},
 		},
 	})
 }
@@ -195,6 +218,166 @@ func TestAccOpsGenieTeam_complete(t *testing.T) {
 	})
 }
 
+func TestAccOpsGenieTeam_adoptDefaultResources(t *testing.T) {
+	randomTeam := acctest.RandString(6)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testCheckOpsGenieTeamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOpsGenieTeam_adoptDefaultResources(randomTeam, "Adopted default schedule"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckOpsGenieTeamExists("opsgenie_team.test"),
+					resource.TestCheckResourceAttrSet("opsgenie_team.test", "default_schedule_id"),
+					resource.TestCheckResourceAttrSet("opsgenie_team.test", "default_escalation_id"),
+					resource.TestCheckResourceAttrSet("opsgenie_team.test", "default_routing_rule_id"),
+					resource.TestCheckResourceAttr("opsgenie_team.test", "default_schedule.0.description", "Adopted default schedule"),
+					resource.TestCheckResourceAttr("opsgenie_team.test", "default_schedule.0.timezone", "Europe/Rome"),
+				),
+			},
+			{
+				Config: testAccOpsGenieTeam_adoptDefaultResources(randomTeam, "Updated default schedule"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("opsgenie_team.test", "default_schedule.0.description", "Updated default schedule"),
+				),
+			},
+		},
+	})
+}
+
+func TestCustomizeDiffTeamDefaultResources(t *testing.T) {
+	for _, adopt := range []bool{false, true} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                    "platform",
+			"adopt_default_resources": adopt,
+			"default_schedule":        []interface{}{map[string]interface{}{"description": "Platform schedule"}},
+		})
+		_, err := resourceOpsGenieTeam().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		if adopt && err != nil {
+			t.Errorf("Expected default_schedule to be valid when adopting the default resources, got %s", err)
+		}
+		if !adopt && (err == nil || !strings.Contains(err.Error(), "default_schedule can only be set when adopt_default_resources is true")) {
+			t.Errorf("Expected default_schedule to be rejected without adopting the default resources, got %v", err)
+		}
+	}
+}
+
+func TestCustomizeDiffTeamDefaultEscalationRules(t *testing.T) {
+	cases := []struct {
+		rules    []interface{}
+		expected string
+	}{
+		{
+			rules: []interface{}{testEscalationRule(0, "default", "user"), testEscalationRule(5, "next", "schedule")},
+		},
+		{
+			rules:    []interface{}{testEscalationRule(5, "default", "user"), testEscalationRule(5, "default", "team")},
+			expected: "default_escalation.0.rules: more than one rule has the same delay, the delays of rules must be unique",
+		},
+	}
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                    "platform",
+			"adopt_default_resources": true,
+			"default_escalation":      []interface{}{map[string]interface{}{"rules": c.rules}},
+		})
+		_, err := resourceOpsGenieTeam().SimpleDiff(context.Background(), &terraform.InstanceState{}, config, nil)
+		if c.expected == "" && err != nil {
+			t.Errorf("Expected %v to be valid, got %s", c.rules, err)
+		}
+		if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("Expected %q, got %v", c.expected, err)
+		}
+	}
+}
+
+func TestUpdateAdoptedDefaultResources_notifyType(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceOpsGenieTeam().Schema, map[string]interface{}{
+		"name":                    "platform",
+		"adopt_default_resources": true,
+		"default_escalation_id":   "escalation-id",
+		"default_escalation":      []interface{}{map[string]interface{}{"rules": []interface{}{testEscalationRule(0, "all", "user")}}},
+	})
+	expected := `default_escalation.0.rules: the rule with delay 0 has notify_type "all", which only applies to team recipients, not user`
+	if err := updateAdoptedDefaultResources(context.Background(), d, &ogClient.Config{ApiKey: "key"}); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, got %v", expected, err)
+	}
+}
+
+func TestResourceOpsGenieTeamUpdate_adoptDefaultResources(t *testing.T) {
+	server := fakeopsgenie.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	meta, err := (&Config{ApiKey: "key", ApiUrl: server.ApiUrl(), RetryCount: 1}).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceOpsGenieTeam()
+	created := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "platform"})
+	if diags := resourceOpsGenieTeamCreate(ctx, created, meta); diags.HasError() {
+		t.Fatal(diags)
+	}
+	state := created.State()
+
+	// adding a block to a team that does not adopt its default resources
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":             "platform",
+		"default_schedule": []interface{}{map[string]interface{}{"description": "Platform schedule"}},
+	})
+	if _, err := r.SimpleDiff(ctx, state, config, meta); err == nil || !strings.Contains(err.Error(), "default_schedule can only be set when adopt_default_resources is true") {
+		t.Errorf("Expected default_schedule to be rejected on update, got %v", err)
+	}
+
+	// adopting the default resources of the existing team
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                    "platform",
+		"adopt_default_resources": true,
+		"default_schedule":        []interface{}{map[string]interface{}{"description": "Platform schedule"}},
+	})
+	diff, err := r.SimpleDiff(ctx, state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"default_schedule_id", "default_escalation_id", "default_routing_rule_id"} {
+		if attr, ok := diff.Attributes[k]; !ok || !attr.NewComputed {
+			t.Errorf("Expected %s to be known after the apply, got %v", k, attr)
+		}
+	}
+	state, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	for _, k := range []string{"default_schedule_id", "default_escalation_id", "default_routing_rule_id"} {
+		if state.Attributes[k] == "" {
+			t.Errorf("Expected %s to be adopted, got %v", k, state.Attributes)
+		}
+	}
+	if description := state.Attributes["default_schedule.0.description"]; description != "Platform schedule" {
+		t.Errorf("Expected the default schedule to be updated, got %q", description)
+	}
+
+	// no longer adopting them, the blocks left in the state are dropped
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{"name": "platform"})
+	diff, err = r.SimpleDiff(ctx, state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, diags = r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	for _, k := range []string{"default_schedule_id", "default_escalation_id", "default_routing_rule_id"} {
+		if state.Attributes[k] != "" {
+			t.Errorf("Expected %s to be cleared, got %q", k, state.Attributes[k])
+		}
+	}
+	if n := state.Attributes["default_schedule.#"]; n != "0" && n != "" {
+		t.Errorf("Expected default_schedule to be cleared, got %v", state.Attributes)
+	}
+}
+
 func testCheckOpsGenieTeamDestroy(s *terraform.State) error {
 	client, err := team.NewClient(testAccProvider.Meta().(*OpsgenieClient).client.Config)
 	if err != nil {
@@ -292,3 +475,18 @@ resource "opsgenie_team" "test" {
 }
 `, randomUser, randomTeam)
 }
+
+func testAccOpsGenieTeam_adoptDefaultResources(randomTeam, scheduleDescription string) string {
+	return fmt.Sprintf(`
+resource "opsgenie_team" "test" {
+  name                    = "genieteam-%s"
+  description             = "This team deals with all the things"
+  adopt_default_resources = true
+
+  default_schedule {
+    description = "%s"
+    timezone    = "Europe/Rome"
+  }
+}
+`, randomTeam, scheduleDescription)
+}
@@ -2,6 +2,7 @@ package opsgenie
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"strings"
 	"time"
@@ -15,12 +16,18 @@ import (
 
 func resourceOpsGenieUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceOpsGenieUserCreate,
-		Read:   handleNonExistentResource(resourceOpsGenieUserRead),
-		Update: resourceOpsGenieUserUpdate,
-		Delete: resourceOpsGenieUserDelete,
+		CreateContext: resourceOpsGenieUserCreate,
+		ReadContext:   handleNonExistentResource(resourceOpsGenieUserRead),
+		UpdateContext: resourceOpsGenieUserUpdate,
+		DeleteContext: resourceOpsGenieUserDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceOpsGenieUserImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -40,14 +47,16 @@ func resourceOpsGenieUser() *schema.Resource {
 				ValidateFunc: validateOpsGenieUserRole,
 			},
 			"locale": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "en_US",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "en_US",
+				ValidateFunc: validateLocale,
 			},
 			"timezone": {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Default:          "America/New_York",
+				ValidateFunc:     validateTimezone,
 				DiffSuppressFunc: checkTimeZoneDiff,
 			},
 			"tags": {
@@ -101,20 +110,6 @@ func resourceOpsGenieUser() *schema.Resource {
 	}
 }
 
-func checkTimeZoneDiff(k, old, new string, d *schema.ResourceData) bool {
-	locationOld, errOld := time.LoadLocation(old)
-	if errOld != nil {
-		return false
-	}
-	locationNew, errNew := time.LoadLocation(new)
-	if errNew != nil {
-		return false
-	}
-	now := time.Now()
-	timeOld := now.In(locationOld)
-	timeNew := now.In(locationNew)
-	return timeOld.Format(time.ANSIC) == timeNew.Format(time.ANSIC)
-}
 func expandOpsGenieUsertags(input *schema.Set) []string {
 	output := make([]string, 0)
 
@@ -165,11 +160,11 @@ func expandOpsGenieUserDetails(d *schema.ResourceData) map[string][]string {
 	return output
 }
 
-func resourceOpsGenieUserCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client, err := user.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	username := d.Get("username").(string)
 	fullName := d.Get("full_name").(string)
@@ -202,17 +197,39 @@ func resourceOpsGenieUserCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	log.Printf("[INFO] Creating OpsGenie user '%s'", username)
-	result, err := client.Create(context.Background(), createRequest)
+	result, err := client.Create(ctx, createRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(result.Id)
 
-	return resourceOpsGenieUserRead(d, meta)
+	return diag.FromErr(resourceOpsGenieUserRead(ctx, d, meta))
+}
+
+// resourceOpsGenieUserImport accepts the username of a user as well as the ID,
+// as the API identifies users by either of them.
+func resourceOpsGenieUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.Contains(d.Id(), "@") {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	client, err := user.NewClient(meta.(*OpsgenieClient).client.Config)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, &user.GetRequest{
+		Identifier: d.Id(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not find user %q to import: %s", d.Id(), err)
+	}
+
+	d.SetId(result.Id)
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceOpsGenieUserRead(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	client, err := user.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
 		return err
@@ -221,7 +238,7 @@ func resourceOpsGenieUserRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[INFO] Reading OpsGenie user '%s'", username)
 
-	usr, err := client.Get(context.Background(), &user.GetRequest{
+	usr, err := client.Get(ctx, &user.GetRequest{
 		Identifier: d.Id(),
 	})
 	if err != nil {
@@ -242,10 +259,10 @@ func resourceOpsGenieUserRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceOpsGenieUserUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := user.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	username := d.Get("username").(string)
 	fullName := d.Get("full_name").(string)
@@ -279,27 +296,27 @@ func resourceOpsGenieUserUpdate(d *schema.ResourceData, meta interface{}) error
 		SkypeUsername: skypeUsername,
 	}
 
-	_, err = client.Update(context.Background(), updateRequest)
+	_, err = client.Update(ctx, updateRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceOpsGenieUserDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceOpsGenieUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Deleting OpsGenie user '%s'", d.Get("username").(string))
 	client, err := user.NewClient(meta.(*OpsgenieClient).client.Config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	deleteRequest := &user.DeleteRequest{
 		Identifier: d.Id(),
 	}
 
-	_, err = client.Delete(context.Background(), deleteRequest)
+	_, err = client.Delete(ctx, deleteRequest)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/user"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -25,7 +24,7 @@ func init() {
 }
 
 func testSweepUserContact(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -44,7 +43,7 @@ func testSweepUserContact(region string) error {
 		return err
 	}
 	for _, u := range respUser.Users {
-		if strings.HasPrefix(u.Username, "genietest-") {
+		if isSweepable(u.Username) {
 			resp, err := client.List(context.Background(), &contact.ListRequest{
 				UserIdentifier: u.Id,
 			})
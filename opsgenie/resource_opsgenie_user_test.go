@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"regexp"
-	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -19,12 +18,19 @@ import (
 func init() {
 	resource.AddTestSweepers("opsgenie_user", &resource.Sweeper{
 		Name: "opsgenie_user",
-		F:    testSweepUser,
+		Dependencies: []string{
+			"opsgenie_user_contact",
+			"opsgenie_notification_rule",
+			"opsgenie_team",
+			"opsgenie_schedule_rotation",
+			"opsgenie_escalation",
+		},
+		F: testSweepUser,
 	})
 }
 
 func testSweepUser(region string) error {
-	meta, err := sharedConfigForRegion()
+	meta, err := sharedConfigForRegion(region)
 	if err != nil {
 		return err
 	}
@@ -39,7 +45,7 @@ func testSweepUser(region string) error {
 	}
 
 	for _, u := range resp.Users {
-		if strings.HasPrefix(u.Username, "genietest-") {
+		if isSweepable(u.Username) {
 			log.Printf("Destroying user %s", u.Username)
 
 			deleteRequest := user.DeleteRequest{
@@ -69,6 +75,12 @@ func TestAccOpsGenieUser_basic(t *testing.T) {
 					testCheckOpsGenieUserExists("opsgenie_user.test"),
 				),
 			},
+			{
+				ResourceName:      "opsgenie_user.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("genietest-%s@opsgenie.com", rs),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
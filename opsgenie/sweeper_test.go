@@ -3,28 +3,100 @@ package opsgenie
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/opsgenie/terraform-provider-opsgenie/internal/fakeopsgenie"
 )
 
 func TestMain(m *testing.M) {
+	// run the acceptance tests against an in-memory fake of the API
+	if os.Getenv("OPSGENIE_FAKE_API") != "" {
+		server := fakeopsgenie.NewServer("fake-api-key")
+		defer server.Close()
+
+		os.Setenv("OPSGENIE_API_KEY", server.ApiKey)
+		os.Setenv("OPSGENIE_API_URL", server.ApiUrl())
+	}
+
 	resource.TestMain(m)
 }
 
-func sharedConfigForRegion() (interface{}, error) {
+// sweepPrefixes are the name prefixes of the resources created by the
+// acceptance tests. Sweepers only delete resources matching one of them.
+var sweepPrefixes = []string{
+	"genietest-",
+	"genietest+",
+	"genieteam",
+	"genieuser-",
+	"genieschedule",
+	"genierotation-",
+	"genieescalation",
+	"genieintegration-",
+	"geniemailintegration-",
+	"genieheartbeat-",
+	"geniemaintenance-",
+	"geniepolicy-",
+	"genie-alert-policy-",
+	"genieservice-",
+	"genierule-",
+}
+
+func isSweepable(name string) bool {
+	for _, prefix := range sweepPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedConfigForRegion configures a client for the region passed with
+// -sweep, e.g. -sweep=eu. Any other value, like -sweep=all, uses the
+// OPSGENIE_API_URL and OPSGENIE_REGION environment variables as the provider
+// does.
+func sharedConfigForRegion(region string) (interface{}, error) {
 	if os.Getenv("OPSGENIE_API_KEY") == "" {
 		return nil, fmt.Errorf("OPSGENIE_API_KEY must be set")
 	}
 
+	apiUrl := string(opsGenieRegions[region])
+	if apiUrl == "" {
+		var err error
+		apiUrl, err = opsGenieApiUrl(os.Getenv("OPSGENIE_API_URL"), os.Getenv("OPSGENIE_REGION"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	config := Config{
-		ApiKey: os.Getenv("OPSGENIE_API_KEY"),
+		ApiKey:           os.Getenv("OPSGENIE_API_KEY"),
+		ApiUrl:           apiUrl,
+		RetryCount:       10,
+		RetryOnRateLimit: true,
 	}
 
 	client, err := config.Client()
 	if err != nil {
-		return nil, fmt.Errorf("error getting OpsGenie client")
+		return nil, fmt.Errorf("error getting OpsGenie client: %s", err)
 	}
 
 	return client, nil
 }
+
+func TestIsSweepable(t *testing.T) {
+	sweepable := []string{"genietest-abc123", "genieteam-abc123_schedule", "genieteam2-abc123", "genieintegration-api-abc123", "genietest+contact-abc123@opsgenie.com"}
+	for _, name := range sweepable {
+		if !isSweepable(name) {
+			t.Fatalf("Expected %q to be swept", name)
+		}
+	}
+
+	kept := []string{"production", "opsgenie-abc123", "my-genietest-team", "genie"}
+	for _, name := range kept {
+		if isSweepable(name) {
+			t.Fatalf("Expected %q not to be swept", name)
+		}
+	}
+}
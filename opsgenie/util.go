@@ -1,25 +1,27 @@
 package opsgenie
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
 )
 
 // handleNonExistentResource is a wrapper of resourceFunc that
 // handles errors returned by a read function.
-func handleNonExistentResource(f schema.ReadFunc) schema.ReadFunc {
-	return func(d *schema.ResourceData, meta interface{}) error {
-		if err := f(d, meta); err != nil {
+func handleNonExistentResource(f func(context.Context, *schema.ResourceData, interface{}) error) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if err := f(ctx, d, meta); err != nil {
 			// if the error that we receive is an ApiError and
 			// the status code is 404, it means we need to re-create
 			// the specific resource
-			apiErr, ok := err.(*client.ApiError)
-			if !ok || apiErr.StatusCode != http.StatusNotFound {
-				return err
+			if !isOpsGenieNotFound(err) {
+				return diag.FromErr(err)
 			}
 			d.SetId("")
 			return nil
@@ -29,6 +31,86 @@ func handleNonExistentResource(f schema.ReadFunc) schema.ReadFunc {
 	}
 }
 
+// isOpsGenieNotFound reports whether err is an ApiError with a 404 status code.
+func isOpsGenieNotFound(err error) bool {
+	apiErr, ok := err.(*client.ApiError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// checkTimeZoneDiff suppresses the diff between two names of the same time
+// zone, like an alias and its canonical name, e.g. US/Eastern and
+// America/New_York. Other time zones are equivalent when they have the same
+// UTC offset at every hour of the coming year, e.g. America/Los_Angeles and
+// Canada/Pacific.
+func checkTimeZoneDiff(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	if canonicalTimeZone(old) == canonicalTimeZone(new) {
+		return true
+	}
+	locationOld, errOld := time.LoadLocation(old)
+	if errOld != nil {
+		return false
+	}
+	locationNew, errNew := time.LoadLocation(new)
+	if errNew != nil {
+		return false
+	}
+
+	start := time.Now().UTC().Truncate(time.Hour)
+	for t := start; t.Before(start.AddDate(1, 0, 0)); t = t.Add(time.Hour) {
+		_, offsetOld := t.In(locationOld).Zone()
+		_, offsetNew := t.In(locationNew).Zone()
+		if offsetOld != offsetNew {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalTimeZone returns the canonical name of the time zone, see
+// timeZoneLinks.
+func canonicalTimeZone(name string) string {
+	if canonical, ok := timeZoneLinks[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// validateTimezone checks that the value is a time zone of the IANA time
+// zone database.
+func validateTimezone(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := time.LoadLocation(value); err != nil || value == "" || value == "Local" {
+		errors = append(errors, fmt.Errorf("expected %s to be a time zone of the IANA time zone database, like Europe/Rome, got %q", k, value))
+	}
+	return
+}
+
+// opsgenieLocales are the locales that OpsGenie supports, see
+// https://docs.opsgenie.com/docs/supported-locales
+var opsgenieLocales = []string{
+	"ar", "ar_AE", "ar_BH", "ar_DZ", "ar_EG", "ar_IQ", "ar_JO", "ar_KW", "ar_LB", "ar_LY", "ar_MA", "ar_OM", "ar_QA", "ar_SA", "ar_SD", "ar_SY", "ar_TN", "ar_YE",
+	"be", "be_BY", "bg", "bg_BG", "ca", "ca_ES", "cs", "cs_CZ", "da", "da_DK",
+	"de", "de_AT", "de_CH", "de_DE", "de_GR", "de_LU", "el", "el_CY", "el_GR",
+	"en", "en_AU", "en_CA", "en_GB", "en_IE", "en_IN", "en_MT", "en_NZ", "en_PH", "en_SG", "en_US", "en_ZA",
+	"es", "es_AR", "es_BO", "es_CL", "es_CO", "es_CR", "es_CU", "es_DO", "es_EC", "es_ES", "es_GT", "es_HN", "es_MX", "es_NI", "es_PA", "es_PE", "es_PR", "es_PY", "es_SV", "es_US", "es_UY", "es_VE",
+	"et", "et_EE", "fi", "fi_FI", "fr", "fr_BE", "fr_CA", "fr_CH", "fr_FR", "fr_LU", "ga", "ga_IE", "hi", "hi_IN", "hr", "hr_HR", "hu", "hu_HU",
+	"in", "in_ID", "is", "is_IS", "it", "it_CH", "it_IT", "iw", "iw_IL", "ja", "ja_JP", "ko", "ko_KR", "lt", "lt_LT", "lv", "lv_LV",
+	"mk", "mk_MK", "ms", "ms_MY", "mt", "mt_MT", "nl", "nl_BE", "nl_NL", "no", "no_NO", "pl", "pl_PL", "pt", "pt_BR", "pt_PT",
+	"ro", "ro_RO", "ru", "ru_RU", "sk", "sk_SK", "sl", "sl_SI", "sq", "sq_AL", "sr", "sr_BA", "sr_CS", "sr_ME", "sr_RS", "sv", "sv_SE",
+	"th", "th_TH", "tr", "tr_TR", "uk", "uk_UA", "vi", "vi_VN", "zh", "zh_CN", "zh_HK", "zh_SG", "zh_TW",
+}
+
+func validateLocale(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !stringInSlice(value, opsgenieLocales) {
+		errors = append(errors, fmt.Errorf("expected %s to be a locale supported by OpsGenie, like en_US, got %q", k, value))
+	}
+	return
+}
+
 func validateDateWithMinutes(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 
@@ -59,6 +141,128 @@ func validateDate(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
+// importNamePrefix marks import IDs that are names instead of IDs, e.g.
+// terraform import opsgenie_team.platform name:platform
+const importNamePrefix = "name:"
+
+func importName(id string) (string, bool) {
+	if !strings.HasPrefix(id, importNamePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(id, importNamePrefix), true
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration like 500ms, 30s or 2m: %q", k, value))
+		return
+	}
+	if duration < 0 {
+		errors = append(errors, fmt.Errorf("%q cannot be negative: %q", k, value))
+	}
+
+	return
+}
+
+func validateApiUrl(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if strings.Contains(value, "://") {
+		errors = append(errors, fmt.Errorf("%q must be a host name like api.eu.opsgenie.com without a scheme: %q", k, value))
+	} else if strings.ContainsAny(value, "/?# ") {
+		errors = append(errors, fmt.Errorf("%q must be a host name like api.eu.opsgenie.com without a path: %q", k, value))
+	}
+
+	return
+}
+
+// normalizeResponderIdentifiers keeps only the identifiers that the
+// responder-like blocks in prior, the configuration or state, were given with,
+// so that referencing responders by name or username does not cause a diff
+// once the API returns their IDs. Responders without a prior block of the same
+// type, e.g. after an import, are referenced by ID.
+func normalizeResponderIdentifiers(responders []map[string]interface{}, prior []interface{}) []map[string]interface{} {
+	for i, responder := range responders {
+		var priorResponder map[string]interface{}
+		if i < len(prior) {
+			priorResponder, _ = prior[i].(map[string]interface{})
+		}
+		if priorResponder == nil || fmt.Sprint(priorResponder["type"]) != fmt.Sprint(responder["type"]) {
+			priorResponder = map[string]interface{}{"id": responder["id"]}
+		}
+
+		byName := false
+		for _, k := range []string{"name", "username"} {
+			if _, ok := responder[k]; !ok {
+				continue
+			}
+			priorValue, _ := priorResponder[k].(string)
+			if priorValue == "" {
+				responder[k] = ""
+				continue
+			}
+			byName = true
+			// the API may return names in a different case than configured
+			if value := fmt.Sprint(responder[k]); value == "" || strings.EqualFold(value, priorValue) {
+				responder[k] = priorValue
+			}
+		}
+		if priorId, _ := priorResponder["id"].(string); byName && priorId == "" {
+			responder["id"] = ""
+		}
+	}
+	return responders
+}
+
+// normalizeResponderSetIdentifiers is normalizeResponderIdentifiers for
+// responders kept in a set, which are matched with the prior block of the same
+// type that references them by ID, name or username instead of by position.
+func normalizeResponderSetIdentifiers(responders []map[string]interface{}, prior []interface{}) []map[string]interface{} {
+	matched := make([]interface{}, len(responders))
+	used := make([]bool, len(prior))
+	for i, responder := range responders {
+		for j, p := range prior {
+			priorResponder, ok := p.(map[string]interface{})
+			if ok && !used[j] && sameResponder(responder, priorResponder) {
+				matched[i] = priorResponder
+				used[j] = true
+				break
+			}
+		}
+	}
+	return normalizeResponderIdentifiers(responders, matched)
+}
+
+func sameResponder(responder, prior map[string]interface{}) bool {
+	if fmt.Sprint(responder["type"]) != fmt.Sprint(prior["type"]) {
+		return false
+	}
+	if id, _ := prior["id"].(string); id != "" && id == fmt.Sprint(responder["id"]) {
+		return true
+	}
+	for _, k := range []string{"name", "username"} {
+		if value, _ := prior[k].(string); value != "" && strings.EqualFold(value, fmt.Sprint(responder[k])) {
+			return true
+		}
+	}
+	return false
+}
+
+// responderKey identifies a responder-like block by its type and the
+// identifiers it is referenced with, ignoring case.
+func responderKey(responder map[string]interface{}) string {
+	return strings.ToLower(fmt.Sprintf("%v-%v-%v-%v", responder["type"], responder["id"], responder["name"], responder["username"]))
+}
+
+// responderHash hashes responders kept in a set by responderKey, so that a
+// responder referenced by name keeps its hash once the API returns its ID.
+func responderHash(v interface{}) int {
+	return schema.HashString(responderKey(v.(map[string]interface{})))
+}
+
 func convertStringMapToInterfaceMap(old map[string]string) map[string]interface{} {
 	new := map[string]interface{}{}
 	for k, v := range old {